@@ -16,7 +16,7 @@ func ParseFlags(startConfig Config, appVersion string) (Config, time.Time, bool,
 	// Check for any changes
 	var changed bool
 	// Define configuration flags
-	var timezones, symbols, tics, stretch, inline, colorize, hours12, live, sorting, sortLocalTop string
+	var timezones, symbols, colorMode, segmentation, midnight, view, layout, calendars, roster, tags, output, statusBar, export, duration, title, importTarget, importColumns, addr, lineTemplate, lineIndicators, tics, stretch, inline, colorize, hours12, precision, formatInfo, formatHeader, formatTics, locale, columns, live, sorting, sortLocalTop string
 	flag.StringVar(
 		&timezones,
 		"timezones",
@@ -33,6 +33,139 @@ func ParseFlags(startConfig Config, appVersion string) (Config, time.Time, bool,
 			SymbolModeSunMoon+", "+
 			SymbolModeMono+")",
 	)
+	flag.StringVar(
+		&colorMode,
+		"color-mode",
+		"",
+		"indicates how to colorize the bars, if colorized (one of: "+
+			ColorModeSegments+" - by day segment, "+
+			ColorModeGradient+" - smooth gradient by time of day or sun elevation, needs true color support)",
+	)
+	flag.StringVar(
+		&segmentation,
+		"segmentation",
+		"",
+		"indicates how to segment the day (one of: "+
+			SegmentationModeClock+" - configured times, "+
+			SegmentationModeSun+" - actual sunrise, sunset and twilight, if coordinates are known)",
+	)
+	flag.StringVar(
+		&midnight,
+		"midnight",
+		"",
+		"indicates how to mark the change of the day in the bars (one of: "+
+			MidnightModeNone+", "+
+			MidnightModeMarker+", "+
+			MidnightModeLabel+")",
+	)
+	flag.StringVar(
+		&calendars,
+		"calendars",
+		"",
+		"iCalendar files (.ics) whose events are marked in the bars, comma-separated ('none' removes all)",
+	)
+	flag.StringVar(
+		&roster,
+		"roster",
+		"",
+		"file describing the people of the team (JSON or CSV, 'default' uses roster.json or roster.csv of the config directory)",
+	)
+	flag.StringVar(
+		&tags,
+		"tags",
+		"",
+		"limits the roster to people with any of the given tags, comma-separated, not stored",
+	)
+	flag.StringVar(
+		&output,
+		"format",
+		"",
+		"indicates the output format for this invocation, not stored (one of: "+
+			OutputPlot+" - plot the bars, "+
+			OutputLine+" - print a single line, e.g. for prompts and status bars)",
+	)
+	flag.StringVar(
+		&statusBar,
+		"status-bar",
+		"",
+		"streams updates for a status bar, not stored (one of: "+
+			StatusBarWaybar+" - JSON with text, tooltip, class and percentage, "+
+			StatusBarI3bar+" - i3bar protocol, "+
+			StatusBarPolybar+" - plain text lines)",
+	)
+	flag.StringVar(
+		&export,
+		"export",
+		"",
+		"exports the requested time instead of plotting it, not stored (one of: "+
+			ExportICS+" - iCalendar event using the zone of the time, "+
+			ExportText+" - text listing the time in every location)",
+	)
+	flag.StringVar(
+		&duration,
+		"duration",
+		"",
+		"duration of the exported event (for example: 30m or 1h30m, default: "+formatDuration(ExportDurationDefault)+")",
+	)
+	flag.StringVar(
+		&title,
+		"title",
+		"",
+		"title of the exported event (default: "+ExportTitleDefault+")",
+	)
+	flag.StringVar(
+		&importTarget,
+		"import-target",
+		"",
+		"where 'gotz import <file>' adds the locations or people of a CSV or vCard file, not stored (one of: "+
+			ImportTargetTimezones+", "+
+			ImportTargetRoster+")",
+	)
+	flag.StringVar(
+		&importColumns,
+		"import-columns",
+		"",
+		"CSV columns of the names and timezones to import, comma-separated header names or 1-based indices (default: "+
+			ImportNameColumnDefault+","+ImportTZColumnDefault+"), not stored",
+	)
+	flag.StringVar(
+		&addr,
+		"addr",
+		"",
+		"address 'gotz serve' listens at (default: "+ServeAddrDefault+"), not stored",
+	)
+	flag.StringVar(
+		&lineTemplate,
+		"line-template",
+		"",
+		"template of a location in the single line output (placeholders: {name}, {abbr}, {time}, {zone}, {offset}, {indicator}; 'default' resets it)",
+	)
+	flag.StringVar(
+		&lineIndicators,
+		"line-indicators",
+		"",
+		"indicates how to show the day segment in the single line output (one of: "+
+			IndicatorsNone+", "+
+			IndicatorsColor+" - colorize the locations, even if colors are disabled otherwise, "+
+			IndicatorsEmoji+")",
+	)
+	flag.StringVar(
+		&view,
+		"view",
+		"",
+		"indicates what to plot for this invocation, not stored (one of: "+
+			ViewDay+" - the 24 hours around the time, "+
+			ViewTeam+" - the 24 hours around the time for the zones of the roster, "+
+			ViewWeek+" - a grid of the week counting the locations in their day segment per hour of the reference location, i.e., the one of the requested time)",
+	)
+	flag.StringVar(
+		&layout,
+		"layout",
+		"",
+		"indicates how to plot the locations (one of: "+
+			LayoutHorizontal+" - one bar per location, "+
+			LayoutVertical+" - one column per location, time flowing downward)",
+	)
 	flag.StringVar(
 		&tics,
 		"tics",
@@ -43,7 +176,7 @@ func ParseFlags(startConfig Config, appVersion string) (Config, time.Time, bool,
 		&stretch,
 		"stretch",
 		"",
-		"indicates whether to stretch across the terminal width at cost of accuracy (one of: true, false)",
+		"indicates whether to stretch across the terminal width (slots may not align with full hours) (one of: true, false)",
 	)
 	flag.StringVar(
 		&inline,
@@ -63,6 +196,52 @@ func ParseFlags(startConfig Config, appVersion string) (Config, time.Time, bool,
 		"",
 		"indicates whether to use 12-hour clock (one of: true, false)",
 	)
+	flag.StringVar(
+		&precision,
+		"precision",
+		"",
+		"precision of the clock shown, also defines the refresh rate in live mode (one of: "+
+			PrecisionMinutes+", "+
+			PrecisionSeconds+")",
+	)
+	flag.StringVar(
+		&formatInfo,
+		"format-info",
+		"",
+		"format of the time shown per location as Go layout (e.g. 'Mon 02 Jan 15:04') or strftime-style (e.g. '%a %F %H:%M') ('"+
+			formatDefault+"' resets it)",
+	)
+	flag.StringVar(
+		&formatHeader,
+		"format-header",
+		"",
+		"format of the time shown in the header as Go layout or strftime-style ('"+formatDefault+"' resets it)",
+	)
+	flag.StringVar(
+		&formatTics,
+		"format-tics",
+		"",
+		"format of the tic labels as Go layout or strftime-style ('"+formatDefault+"' resets it)",
+	)
+	flag.StringVar(
+		&locale,
+		"locale",
+		"",
+		"language of weekday and month names, also sets the 12/24-hour clock default unless --hours12 is given (e.g. de, pt-BR, ja)",
+	)
+	flag.StringVar(
+		&columns,
+		"columns",
+		"",
+		"info columns to show per location, comma-separated (any of: "+
+			ColumnName+", "+
+			ColumnTime+", "+
+			ColumnAbbreviation+", "+
+			ColumnOffset+", "+
+			ColumnRelative+", "+
+			ColumnDay+", "+
+			ColumnTZ+")",
+	)
 	flag.StringVar(
 		&live,
 		"live",
@@ -92,11 +271,12 @@ func ParseFlags(startConfig Config, appVersion string) (Config, time.Time, bool,
 		&requestTime,
 		"time",
 		"",
-		"time to display (e.g. 20:00 or 2000 or 20 or 8pm)",
+		"time to display (e.g. 20:00 or 2000 or 20 or 8pm, dates like 2023-10-11 14:00 or 10/11 3pm, Unix time or RFC 2822; also ranges like 15:00-16:30)",
 	)
 
-	// Parse flags
-	flag.Parse()
+	// Parse flags (also the ones given after commands or files, e.g.
+	// 'gotz serve --addr :8080')
+	_ = flag.CommandLine.Parse(flagsFirst(flag.CommandLine, os.Args[1:]))
 
 	// Check for version flag
 	if *version {
@@ -121,6 +301,133 @@ func ParseFlags(startConfig Config, appVersion string) (Config, time.Time, bool,
 			return startConfig, rt, changed, symbolError
 		}
 	}
+	if colorMode != "" {
+		changed = true
+		if err := checkColorMode(colorMode); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Style.ColorMode = colorMode
+	}
+	if segmentation != "" {
+		changed = true
+		if err := checkSegmentationMode(segmentation); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Style.DaySegmentation.Mode = segmentation
+	}
+	if midnight != "" {
+		changed = true
+		if err := checkMidnightMode(midnight); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Style.Midnight = midnight
+	}
+	if calendars != "" {
+		changed = true
+		startConfig.Calendars = nil
+		if calendars != "none" {
+			for _, file := range strings.Split(calendars, ",") {
+				if file = strings.TrimSpace(file); file != "" {
+					startConfig.Calendars = append(startConfig.Calendars, file)
+				}
+			}
+		}
+	}
+	if roster != "" {
+		changed = true
+		if roster == formatDefault {
+			roster = ""
+		}
+		startConfig.Roster = roster
+	}
+	if tags != "" {
+		// Only applies to this invocation, hence, the config is not changed
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				startConfig.RosterTags = append(startConfig.RosterTags, tag)
+			}
+		}
+	}
+	if output != "" {
+		// Only applies to this invocation, hence, the config is not changed
+		if err := checkOutput(output); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Output = output
+	}
+	if statusBar != "" {
+		// Only applies to this invocation, hence, the config is not changed
+		if err := checkStatusBar(statusBar); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.StatusBar = statusBar
+	}
+	if export != "" {
+		// Only applies to this invocation, hence, the config is not changed
+		if err := checkExport(export); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Export = ExportOptions{
+			Format:   export,
+			Duration: ExportDurationDefault,
+			Title:    title,
+		}
+		if duration != "" {
+			d, err := time.ParseDuration(duration)
+			if err != nil || d < 0 {
+				return startConfig, rt, changed, fmt.Errorf("invalid duration: %s", duration)
+			}
+			startConfig.Export.Duration = d
+		}
+	}
+	if importTarget != "" {
+		// Only applies to this invocation, hence, the config is not changed
+		if err := checkImportTarget(importTarget); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Import.Target = importTarget
+	}
+	if importColumns != "" {
+		// Only applies to this invocation, hence, the config is not changed
+		nameColumn, tzColumn, found := strings.Cut(importColumns, ",")
+		if !found || strings.TrimSpace(nameColumn) == "" || strings.TrimSpace(tzColumn) == "" {
+			return startConfig, rt, changed, fmt.Errorf("invalid import columns: %s (should be <name-column>,<tz-column>)", importColumns)
+		}
+		startConfig.Import.NameColumn = strings.TrimSpace(nameColumn)
+		startConfig.Import.TZColumn = strings.TrimSpace(tzColumn)
+	}
+	if addr != "" {
+		// Only applies to this invocation, hence, the config is not changed
+		startConfig.ServeAddr = addr
+	}
+	if lineTemplate != "" {
+		changed = true
+		if lineTemplate == formatDefault {
+			lineTemplate = LineTemplateDefault
+		}
+		startConfig.Line.Template = lineTemplate
+	}
+	if lineIndicators != "" {
+		changed = true
+		if err := checkIndicators(lineIndicators); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Line.Indicators = lineIndicators
+	}
+	if view != "" {
+		// Only applies to this invocation, hence, the config is not changed
+		if err := checkView(view); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.View = view
+	}
+	if layout != "" {
+		changed = true
+		if err := checkLayout(layout); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Layout = layout
+	}
 	if tics != "" {
 		changed = true
 		if strings.ToLower(tics) == "true" {
@@ -171,6 +478,53 @@ func ParseFlags(startConfig Config, appVersion string) (Config, time.Time, bool,
 			return startConfig, rt, changed, fmt.Errorf("invalid value for hours12: %s", hours12)
 		}
 	}
+	if precision != "" {
+		changed = true
+		if err := checkPrecision(precision); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Precision = precision
+	}
+	for _, format := range []struct {
+		value  string
+		target *string
+	}{
+		{formatInfo, &startConfig.Formats.Info},
+		{formatHeader, &startConfig.Formats.Header},
+		{formatTics, &startConfig.Formats.Tics},
+	} {
+		if format.value == "" {
+			continue
+		}
+		changed = true
+		if format.value == formatDefault {
+			*format.target = ""
+			continue
+		}
+		if err := checkTimeFormat(format.value); err != nil {
+			return startConfig, rt, changed, err
+		}
+		*format.target = format.value
+	}
+	if locale != "" {
+		changed = true
+		if err := checkLocale(locale); err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Locale = locale
+		// Use the clock common for the locale, if not explicitly given
+		if hours12 == "" {
+			startConfig.Hours12 = getLocale(locale).hours12
+		}
+	}
+	if columns != "" {
+		changed = true
+		cols, err := parseColumns(columns)
+		if err != nil {
+			return startConfig, rt, changed, err
+		}
+		startConfig.Columns = cols
+	}
 	if live != "" {
 		changed = true
 		if strings.ToLower(live) == "true" {
@@ -199,34 +553,89 @@ func ParseFlags(startConfig Config, appVersion string) (Config, time.Time, bool,
 		}
 	}
 
-	// Handle direct flags
+	// Handle direct flags and arguments as requested times (starting with a
+	// digit, optionally labeled, ignoring the file of an import or the
+	// argument of a server)
+	var requests []string
 	if requestTime != "" {
-		// Parse time
-		rTime, err := ParseRequestTime(startConfig, requestTime)
+		requests = append(requests, requestTime)
+	}
+	if flag.Arg(0) != CommandImport && flag.Arg(0) != CommandServe {
+		for _, arg := range flag.Args() {
+			if isMarkArg(startConfig, arg) {
+				requests = append(requests, arg)
+			}
+		}
+	}
+	if len(requests) > 0 {
+		marks, err := parseMarks(startConfig, requests)
 		if err != nil {
 			return startConfig, rt, changed, err
 		}
-		rt = rTime
-	}
-
-	// Handle last argument as time, if it starts with a digit
-	if flag.NArg() > 0 {
-		// Get last argument
-		lastArg := flag.Arg(flag.NArg() - 1)
-		// If last argument is a time, parse it
-		if len(lastArg) > 0 && lastArg[0] >= '0' && lastArg[0] <= '9' {
-			// Parse time
-			rTime, err := ParseRequestTime(startConfig, lastArg)
-			if err != nil {
-				return startConfig, rt, changed, err
-			}
-			rt = rTime
+		rt = marks[0].Time
+		// Highlight the first requested time, if it is a range
+		first := requests[0]
+		if _, value, found := strings.Cut(first, "="); found {
+			first = value
+		}
+		if startConfig.Range, err = ParseRequestRange(startConfig, first); err != nil {
+			return startConfig, rt, changed, err
+		}
+		// Mark the times in the bars, if several or labeled ones are given
+		if len(marks) > 1 || marks[0].Label != "" {
+			startConfig.Marks = marks
 		}
 	}
 
 	return startConfig, rt, changed, nil
 }
 
+// Define commands (given as first argument)
+const (
+	// CommandWho lists who of the roster is working, available or asleep.
+	CommandWho = "who"
+	// CommandImport imports locations or people from a CSV or vCard file.
+	CommandImport = "import"
+	// CommandServe serves an HTTP API and an auto-refreshing page.
+	CommandServe = "serve"
+)
+
+// flagsFirst reorders the arguments such that all flags (and their values)
+// precede the positional arguments, which keep their order. Arguments following
+// a "--" terminator are positional.
+func flagsFirst(fs *flag.FlagSet, args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			positional = append(positional, arg)
+			continue
+		}
+		flags = append(flags, arg)
+		// Take the value of non-boolean flags given as separate argument
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			continue
+		}
+		if i+1 < len(args) {
+			flags = append(flags, args[i+1])
+			i++
+		}
+	}
+	return append(flags, positional...)
+}
+
 // parseTimezones parses a comma-separated list of timezones.
 func parseTimezones(timezones string) ([]Location, error) {
 	var timezoneList []Location
@@ -265,7 +674,7 @@ func parseTimezones(timezones string) ([]Location, error) {
 
 // checkTimezoneLocation checks if a timezone name is valid.
 func checkTimezoneLocation(timezone string) bool {
-	_, err := time.LoadLocation(timezone)
+	_, err := loadLocation(timezone)
 	return err == nil
 }
 
@@ -277,83 +686,152 @@ type inputTimeFormat struct {
 	Date bool
 	// Indicates whether the input declared a timezone too.
 	TZInfo bool
+	// Indicates whether the declared date lacks the year (the current one is
+	// used).
+	NoYear bool
 }
 
+// inputTimeFormats are the accepted time formats (tried in order). Numeric
+// dates with slashes are read as month/day (US), the ones with dots as
+// day.month. (European).
+var inputTimeFormats = func() []inputTimeFormat {
+	// Times of the day (today)
+	formats := []inputTimeFormat{
+		{"15", false, false, false},
+		{"15:04", false, false, false},
+		{"15:04:05", false, false, false},
+		{"3:04pm", false, false, false},
+		{"3:04:05pm", false, false, false},
+		{"3pm", false, false, false},
+		{"1504", false, false, false},
+		{"150405", false, false, false},
+	}
+	// ISO 8601 / RFC 3339 (also separated by space and with numeric or UTC
+	// zones, as commonly found in logs)
+	for _, sep := range []string{"T", " "} {
+		for _, clock := range []string{"15:04:05", "15:04"} {
+			layout := "2006-01-02" + sep + clock
+			formats = append(formats,
+				inputTimeFormat{layout, true, false, false},
+				inputTimeFormat{layout + "Z07:00", true, true, false},
+				inputTimeFormat{layout + " -0700", true, true, false},
+				inputTimeFormat{layout + " UTC", true, true, false},
+			)
+		}
+	}
+	// RFC 2822 / RFC 1123 with numeric zone (e.g. of e-mails and HTTP headers)
+	formats = append(formats,
+		inputTimeFormat{"Mon, 2 Jan 2006 15:04:05 -0700", true, true, false},
+		inputTimeFormat{"2 Jan 2006 15:04:05 -0700", true, true, false},
+		inputTimeFormat{"Mon, 2 Jan 2006 15:04:05 UTC", true, true, false},
+		inputTimeFormat{"Mon, 2 Jan 2006 15:04:05 GMT", true, true, false},
+	)
+	// Dates (at midnight or with a time of the day)
+	for _, date := range []struct {
+		layout string
+		noYear bool
+	}{
+		{"2006-01-02", false},
+		{"1/2/2006", false},
+		{"1/2", true},
+		{"2.1.2006", false},
+		{"2.1.", true},
+	} {
+		formats = append(formats, inputTimeFormat{date.layout, true, false, date.noYear})
+		for _, clock := range []string{"15:04", "15:04:05", "3:04pm", "3:04:05pm", "3pm"} {
+			formats = append(formats, inputTimeFormat{date.layout + " " + clock, true, false, date.noYear})
+		}
+	}
+	return formats
+}()
+
+// inputTimeFormatsHelp describes the accepted time formats (for errors).
+const inputTimeFormatsHelp = "accepted: 15, 15:04, 15:04:05, 3pm, 3:04pm, 1504, 150405, " +
+	"2006-01-02, 2006-01-02 15:04[:05] (also with T, Z07:00, -0700 or UTC), " +
+	"1/2[/2006] [3pm] (month/day), 2.1.[2006] [15:04] (day.month.), " +
+	"Mon, 2 Jan 2006 15:04:05 -0700, Unix time in seconds (9+ digits) or milliseconds (12+ digits)"
+
 // ParseRequestTime parses a requested time in various formats. Furthermore, it
-// reads an optional timezone index and uses its timezone instead of local.
+// reads an optional timezone index and uses its timezone instead of local. For
+// time ranges (<start>-<end>), the start is returned.
 func ParseRequestTime(config Config, t string) (time.Time, error) {
+	r, err := ParseRequestRange(config, t)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return r.Start, nil
+}
+
+// splitRequestTZ splits the optional timezone from the requested time (given
+// via <time>@<timezone> or as abbreviation or offset after a space, e.g. "3pm
+// PST") and returns the time element and the timezone (local, if none was
+// given).
+func splitRequestTZ(config Config, t string) (string, *time.Location, error) {
 	tzSeparator := "@"
-	tz := time.Local
 	// Check whether a different time zone than the local one was specified.
 	if strings.Contains(t, tzSeparator) {
 		// Split time and timezone
 		parts := strings.Split(t, tzSeparator)
 		if len(parts) != 2 {
-			return time.Time{}, fmt.Errorf("invalid time format: %s (should be <time>@<timezone-index> or <time>@<timezone-identifier>)", t)
-		}
-		if checkTimezoneLocation(parts[1]) {
-			// >>> Handle the explicitly given timezone
-			explicitTZ, err := time.LoadLocation(parts[1])
-			if err == nil {
-				// Use explicit timezone
-				tz = explicitTZ
-			}
-		} else {
-			// >>> Handle the timezone index referring to the configured TZs
-			tzIndex, err := strconv.Atoi(parts[1])
+			return "", nil, fmt.Errorf("invalid time format: %s (should be <time>@<timezone>)", t)
+		}
+		tz, err := resolveRequestZone(config, parts[1])
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid time format: %s (%s)", t, err)
+		}
+		return parts[0], tz, nil
+	}
+	// Check for an abbreviation or offset after the time (unless the time
+	// declares its zone itself, e.g. "2023-10-11 14:00:00 UTC")
+	if i := strings.LastIndex(t, " "); i > 0 && isZoneSuffix(t[i+1:]) {
+		if _, _, err := parseTimeFormat(t, time.Local); err != nil {
+			tz, err := resolveRequestZone(config, t[i+1:])
 			if err != nil {
-				return time.Time{}, fmt.Errorf("invalid time format: %s (should be <time>@<timezone-index> or <time>@<timezone-identifier>)", t)
-			}
-			if tzIndex < 0 || tzIndex > len(config.Timezones) {
-				return time.Time{}, fmt.Errorf("invalid time format: %s (timezone-index out of range)", t)
-			}
-			// Get timezone at index (offset by one to account for 0 as local timezone)
-			if tzIndex > 0 {
-				tz, err = time.LoadLocation(config.Timezones[tzIndex-1].TZ)
-				if err != nil {
-					return time.Time{}, fmt.Errorf("invalid timezone: %s (given index %d)",
-						config.Timezones[tzIndex-1].TZ,
-						tzIndex)
-				}
+				return "", nil, err
 			}
+			return strings.TrimSpace(t[:i]), tz, nil
 		}
-		// Keep time element
-		t = parts[0]
 	}
-	// Parse time
-	rt, err := parseTime(t, tz)
-	if err != nil {
-		return time.Time{}, err
-	}
-	return rt, nil
+	return t, time.Local, nil
 }
 
 // parseTime parses a time string in various formats.
 func parseTime(t string, tz *time.Location) (time.Time, error) {
+	rt, _, err := parseTimeFormat(t, tz)
+	return rt, err
+}
+
+// parseTimeFormat parses a time string in various formats and returns the
+// format matched too.
+func parseTimeFormat(t string, tz *time.Location) (time.Time, inputTimeFormat, error) {
+	t = strings.TrimSpace(t)
+	// Handle Unix time (seconds or milliseconds, distinguished by length)
+	if len(t) >= 9 && strings.Trim(t, "0123456789") == "" {
+		epoch, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return time.Time{}, inputTimeFormat{}, fmt.Errorf("invalid time: %s (%s)", t, inputTimeFormatsHelp)
+		}
+		format := inputTimeFormat{"unix", true, true, false}
+		if len(t) >= 12 {
+			return time.UnixMilli(epoch).In(tz), format, nil
+		}
+		return time.Unix(epoch, 0).In(tz), format, nil
+	}
 	// Try all supported formats
-	for _, format := range []inputTimeFormat{
-		{"15", false, false},
-		{"15:04", false, false},
-		{"15:04:05", false, false},
-		{"3:04pm", false, false},
-		{"3:04:05pm", false, false},
-		{"3pm", false, false},
-		{"1504", false, false},
-		{"150405", false, false},
-		{"2006-01-02T15:04:05", true, false},
-		{"2006-01-02T15:04:05Z07:00", true, true},
-	} {
+	for _, format := range inputTimeFormats {
 		if t, err := time.Parse(format.Format, t); err == nil {
 			n := time.Now()
 			if !format.TZInfo {
-				if format.Date {
-					t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, tz)
+				if format.NoYear {
+					t = time.Date(n.In(tz).Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, tz)
+				} else if format.Date {
+					t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), tz)
 				} else {
 					t = time.Date(n.Year(), n.Month(), n.Day(), t.Hour(), t.Minute(), t.Second(), 0, tz)
 				}
 			}
-			return t, nil
+			return t, format, nil
 		}
 	}
-	return time.Time{}, fmt.Errorf("invalid time: %s", t)
+	return time.Time{}, inputTimeFormat{}, fmt.Errorf("invalid time: %s (%s)", t, inputTimeFormatsHelp)
 }
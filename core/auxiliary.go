@@ -6,6 +6,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/term"
 )
@@ -49,3 +51,25 @@ func AskUser(question string) (bool, error) {
 	// Check input
 	return input == "y" || input == "yes", nil
 }
+
+// locationCache caches the locations loaded by name.
+var locationCache = struct {
+	sync.Mutex
+	locations map[string]*time.Location
+}{locations: map[string]*time.Location{}}
+
+// loadLocation loads the location with the given name. Locations are cached to
+// avoid reading the timezone database over and over again.
+func loadLocation(name string) (*time.Location, error) {
+	locationCache.Lock()
+	defer locationCache.Unlock()
+	if loc, ok := locationCache.locations[name]; ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locationCache.locations[name] = loc
+	return loc, nil
+}
@@ -0,0 +1,658 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventSymbolDefault is the default symbol marking calendar events.
+const EventSymbolDefault = "▚"
+
+// maxOccurrences limits the expansion of recurring events.
+const maxOccurrences = 100000
+
+// event is a single (occurrence of a) calendar event.
+type event struct {
+	// Summary is the title of the event.
+	Summary string
+	// Start of the event.
+	Start time.Time
+	// End of the event (equal to the start, if the event has no duration).
+	End time.Time
+}
+
+// overlaps indicates whether the event overlaps the given slot. Events without
+// duration overlap the slot containing their start.
+func (e event) overlaps(slot timeslot) bool {
+	end := e.End
+	if !end.After(e.Start) {
+		end = e.Start.Add(time.Nanosecond)
+	}
+	return e.Start.Before(slot.Time.Add(slot.Length)) && end.After(slot.Time)
+}
+
+// eventAt indicates whether any of the events overlaps the given slot.
+func eventAt(events []event, slot timeslot) bool {
+	for _, e := range events {
+		if e.overlaps(slot) {
+			return true
+		}
+	}
+	return false
+}
+
+// calendarEntry is a (possibly recurring) event of a calendar.
+type calendarEntry struct {
+	// UID identifies the event (shared by its moved occurrences).
+	UID string
+	// Summary is the title of the event.
+	Summary string
+	// Start of the (first occurrence of the) event.
+	Start time.Time
+	// Length of each occurrence.
+	Length time.Duration
+	// Rule defines the recurrence of the event (nil, if not recurring).
+	Rule *recurrence
+	// Excluded are the starts of cancelled (EXDATE) or moved occurrences.
+	Excluded []time.Time
+	// ExcludedDays are the days of cancelled occurrences given as dates
+	// (e.g. 20060102).
+	ExcludedDays []string
+}
+
+// excluded indicates whether the occurrence starting at the given time was
+// cancelled or moved.
+func (e calendarEntry) excluded(start time.Time) bool {
+	for _, t := range e.Excluded {
+		if t.Equal(start) {
+			return true
+		}
+	}
+	day := start.Format("20060102")
+	for _, d := range e.ExcludedDays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// events returns the occurrences of the entry overlapping the given time range.
+func (e calendarEntry) events(from, to time.Time) []event {
+	starts := []time.Time{e.Start}
+	if e.Rule != nil {
+		starts = e.Rule.occurrences(e.Start, from.Add(-e.Length), to)
+	}
+	var events []event
+	for _, s := range starts {
+		if e.excluded(s) {
+			continue
+		}
+		ev := event{Summary: e.Summary, Start: s, End: s.Add(e.Length)}
+		if ev.Start.Before(to) && !ev.End.Before(from) {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// calendarCache caches the parsed calendars by file (parsing them again only
+// if they were modified).
+var calendarCache = struct {
+	sync.Mutex
+	calendars map[string]cachedCalendar
+}{calendars: map[string]cachedCalendar{}}
+
+// cachedCalendar is a parsed calendar file.
+type cachedCalendar struct {
+	modTime time.Time
+	size    int64
+	entries []calendarEntry
+}
+
+// loadCalendar returns the events of the given iCalendar file (cached until
+// the file is modified).
+func loadCalendar(file string) ([]calendarEntry, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading calendar %s: %s", file, err)
+	}
+	calendarCache.Lock()
+	defer calendarCache.Unlock()
+	if c, ok := calendarCache.calendars[file]; ok && c.modTime.Equal(info.ModTime()) && c.size == info.Size() {
+		return c.entries, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading calendar %s: %s", file, err)
+	}
+	defer f.Close()
+	entries, err := parseICS(f)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing calendar %s: %s", file, err)
+	}
+	calendarCache.calendars[file] = cachedCalendar{modTime: info.ModTime(), size: info.Size(), entries: entries}
+	return entries, nil
+}
+
+// loadEvents reads the events of all given iCalendar files overlapping the
+// given time range (sorted by start).
+func loadEvents(files []string, from, to time.Time) ([]event, error) {
+	var events []event
+	for _, file := range files {
+		entries, err := loadCalendar(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			events = append(events, e.events(from, to)...)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return events, nil
+}
+
+// contentLine is a single content line of an iCalendar or vCard file.
+type contentLine struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// readContentLines reads all content lines (unfolding continued ones).
+func readContentLines(r io.Reader) ([]contentLine, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	properties := make([]contentLine, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		head, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid line: %s", line)
+		}
+		parts := strings.Split(head, ";")
+		property := contentLine{Name: strings.ToUpper(parts[0]), Params: map[string]string{}, Value: value}
+		for _, param := range parts[1:] {
+			key, val, _ := strings.Cut(param, "=")
+			property.Params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+		}
+		properties = append(properties, property)
+	}
+	return properties, nil
+}
+
+// parseICSTime parses a date-time value. Date-only values (all-day) are
+// reported as such. TZIDs that are no IANA identifiers (e.g. the Windows names
+// written by Outlook) use the timezones defined by the calendar, if any, and
+// local time otherwise.
+func parseICSTime(property contentLine, zones map[string]vtimezone) (t time.Time, allDay bool, err error) {
+	value := property.Value
+	if property.Params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err = time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	// Use the given timezone (floating times use local time)
+	loc := time.Local
+	if tzid, ok := property.Params["TZID"]; ok {
+		if l, lErr := loadLocation(tzid); lErr == nil {
+			loc = l
+		} else if zone, ok := zones[tzid]; ok {
+			wall, err := time.Parse("20060102T150405", value)
+			if err != nil {
+				return t, false, err
+			}
+			loc = zone.location(wall)
+		}
+	}
+	t, err = time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// vtimezone is a timezone defined by a calendar (VTIMEZONE), given by its
+// standard and daylight saving time observances.
+type vtimezone []observance
+
+// observance is the standard or daylight saving time of a timezone.
+type observance struct {
+	// Start is the first onset of the observance (local time given as UTC).
+	Start time.Time
+	// Until is the last onset of the observance (zero, if unlimited).
+	Until time.Time
+	// Month, Week and Weekday define the yearly onset, if Month is not zero
+	// (e.g. 3, -1 and Sunday for the last Sunday of March).
+	Month   time.Month
+	Week    int
+	Weekday time.Weekday
+	// Zone is the fixed zone of the observance.
+	Zone *time.Location
+}
+
+// onset returns the latest onset of the observance not after the given local
+// time (given as UTC), if any.
+func (o observance) onset(wall time.Time) (time.Time, bool) {
+	if wall.Before(o.Start) {
+		return time.Time{}, false
+	}
+	if o.Month == 0 {
+		return o.Start, true
+	}
+	for year := wall.Year(); year >= o.Start.Year(); year-- {
+		// Find the nth weekday of the month (counting from its end, if
+		// negative)
+		var day time.Time
+		if o.Week < 0 {
+			day = time.Date(year, o.Month+1, 0, 0, 0, 0, 0, time.UTC)
+			day = day.AddDate(0, 0, -(int(day.Weekday())-int(o.Weekday)+7)%7+7*(o.Week+1))
+		} else {
+			day = time.Date(year, o.Month, 1, 0, 0, 0, 0, time.UTC)
+			day = day.AddDate(0, 0, (int(o.Weekday)-int(day.Weekday())+7)%7+7*(o.Week-1))
+		}
+		t := day.Add(o.Start.Sub(o.Start.Truncate(24 * time.Hour)))
+		if t.After(wall) || t.Before(o.Start) || (!o.Until.IsZero() && t.After(o.Until)) {
+			continue
+		}
+		return t, true
+	}
+	return o.Start, true
+}
+
+// location returns the zone in effect at the given local time (given as UTC).
+// Times before all onsets use the zone of the first observance.
+func (z vtimezone) location(wall time.Time) *time.Location {
+	loc, latest := z[0].Zone, time.Time{}
+	for _, o := range z {
+		if t, ok := o.onset(wall); ok && t.After(latest) {
+			loc, latest = o.Zone, t
+		}
+	}
+	return loc
+}
+
+// parseVTimezones parses the timezones defined by a calendar (by TZID).
+// Observances with unsupported recurrence rules are skipped.
+func parseVTimezones(properties []contentLine) map[string]vtimezone {
+	zones := map[string]vtimezone{}
+	tzid := ""
+	var o *observance
+	for _, property := range properties {
+		switch {
+		case property.Name == "BEGIN" && strings.EqualFold(property.Value, "VTIMEZONE"):
+			tzid = ""
+		case property.Name == "TZID" && o == nil:
+			tzid = property.Value
+		case property.Name == "BEGIN" && (strings.EqualFold(property.Value, "STANDARD") || strings.EqualFold(property.Value, "DAYLIGHT")):
+			o = &observance{}
+		case property.Name == "END" && (strings.EqualFold(property.Value, "STANDARD") || strings.EqualFold(property.Value, "DAYLIGHT")):
+			if o.Zone != nil && !o.Start.IsZero() && tzid != "" {
+				zones[tzid] = append(zones[tzid], *o)
+			}
+			o = nil
+		case o == nil:
+			continue
+		case property.Name == "DTSTART":
+			o.Start, _ = time.Parse("20060102T150405", property.Value)
+		case property.Name == "TZOFFSETTO":
+			o.Zone, _ = parseZoneOffset(property.Value)
+		case property.Name == "RRULE":
+			if !parseObservanceRule(o, property.Value) {
+				o.Start = time.Time{}
+			}
+		}
+	}
+	return zones
+}
+
+// parseObservanceRule parses the yearly rule of an observance (e.g.
+// FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU) and indicates whether it is supported.
+func parseObservanceRule(o *observance, value string) bool {
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			if !strings.EqualFold(val, "YEARLY") {
+				return false
+			}
+		case "BYMONTH":
+			month, err := strconv.Atoi(val)
+			if err != nil || month < 1 || month > 12 {
+				return false
+			}
+			o.Month = time.Month(month)
+		case "BYDAY":
+			if len(val) < 2 {
+				return false
+			}
+			weekday, ok := icsWeekdays[strings.ToUpper(val[len(val)-2:])]
+			if !ok {
+				return false
+			}
+			o.Week, o.Weekday = 1, weekday
+			if n := val[:len(val)-2]; n != "" {
+				week, err := strconv.Atoi(n)
+				if err != nil || week == 0 || week < -5 || week > 5 {
+					return false
+				}
+				o.Week = week
+			}
+		case "UNTIL":
+			until, err := time.Parse("20060102T150405Z", val)
+			if err != nil {
+				if until, err = time.Parse("20060102T150405", val); err != nil {
+					return false
+				}
+			}
+			o.Until = until
+		}
+	}
+	return o.Month != 0 && o.Week != 0
+}
+
+// parseICSDuration parses a duration value (e.g. PT1H30M or P1D).
+func parseICSDuration(value string) (time.Duration, error) {
+	s := value
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	}
+	s = strings.TrimPrefix(s, "+")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+	var d time.Duration
+	inTime := false
+	number := ""
+	for _, r := range s[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		number = ""
+		switch {
+		case r == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+	return sign * d, nil
+}
+
+// recurrence is a (simplified) recurrence rule. Only the frequency, interval,
+// count, until and weekdays (for weekly rules) are supported.
+type recurrence struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    time.Time
+	// UntilDay is the last day given as date (e.g. 20060102), if the rule ends
+	// at a date instead of a time.
+	UntilDay string
+	Weekdays []time.Weekday
+}
+
+// icsWeekdays maps the iCalendar weekday names.
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// parseRecurrence parses a recurrence rule (e.g. FREQ=WEEKLY;BYDAY=MO,WE).
+func parseRecurrence(value string) (recurrence, error) {
+	rule := recurrence{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			// Dates include the occurrences of that day
+			var date bool
+			rule.Until, date, err = parseICSTime(contentLine{Value: val}, nil)
+			if date {
+				rule.Until, rule.UntilDay = time.Time{}, rule.Until.Format("20060102")
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				if wd, ok := icsWeekdays[strings.ToUpper(day)]; ok {
+					rule.Weekdays = append(rule.Weekdays, wd)
+				}
+			}
+		}
+		if err != nil {
+			return rule, fmt.Errorf("invalid recurrence rule: %s", value)
+		}
+	}
+	switch rule.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return rule, fmt.Errorf("unsupported recurrence rule: %s", value)
+	}
+	if rule.Interval < 1 {
+		return rule, fmt.Errorf("invalid recurrence rule: %s", value)
+	}
+	return rule, nil
+}
+
+// occurrences returns the starts of all occurrences of the rule starting at
+// the given time from the given time (skipping earlier periods, if no count
+// limits the rule) until the given time (exclusive).
+func (rule recurrence) occurrences(start, from, to time.Time) []time.Time {
+	// Determine the candidates of a period (weekly rules may have multiple)
+	var offsets []int
+	if rule.Freq == "WEEKLY" && len(rule.Weekdays) > 0 {
+		for _, wd := range rule.Weekdays {
+			offsets = append(offsets, (int(wd)-int(start.Weekday())+7)%7)
+		}
+		sort.Ints(offsets)
+	} else {
+		offsets = []int{0}
+	}
+	// Skip the periods before the given time (keeping one for safety, e.g.
+	// regarding daylight saving time)
+	first := 0
+	if rule.Count == 0 && from.After(start) {
+		days := int(from.Sub(start).Hours() / 24)
+		months := (from.Year()-start.Year())*12 + int(from.Month()-start.Month())
+		switch rule.Freq {
+		case "DAILY":
+			first = days/rule.Interval - 1
+		case "WEEKLY":
+			first = days/(7*rule.Interval) - 1
+		case "MONTHLY":
+			first = months/rule.Interval - 1
+		case "YEARLY":
+			first = months/(12*rule.Interval) - 1
+		}
+		first = max(0, first)
+	}
+	var starts []time.Time
+	count := 0
+	for period := first; count < maxOccurrences; period++ {
+		// Get the start of the period (keeping the wall clock time)
+		var base time.Time
+		switch rule.Freq {
+		case "DAILY":
+			base = start.AddDate(0, 0, period*rule.Interval)
+		case "WEEKLY":
+			base = start.AddDate(0, 0, 7*period*rule.Interval)
+		case "MONTHLY":
+			base = start.AddDate(0, period*rule.Interval, 0)
+		case "YEARLY":
+			base = start.AddDate(period*rule.Interval, 0, 0)
+		}
+		for _, offset := range offsets {
+			occurrence := base.AddDate(0, 0, offset)
+			if !occurrence.Before(to) ||
+				(!rule.Until.IsZero() && occurrence.After(rule.Until)) ||
+				(rule.UntilDay != "" && occurrence.Format("20060102") > rule.UntilDay) ||
+				(rule.Count > 0 && count >= rule.Count) {
+				return starts
+			}
+			starts = append(starts, occurrence)
+			count++
+		}
+	}
+	return starts
+}
+
+// icsUnescaper unescapes text values.
+var icsUnescaper = strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+// parseICS parses the timed events of an iCalendar file (all-day and cancelled
+// events are skipped, moved occurrences replace the original ones).
+func parseICS(r io.Reader) ([]calendarEntry, error) {
+	properties, err := readContentLines(r)
+	if err != nil {
+		return nil, err
+	}
+	zones := parseVTimezones(properties)
+	var entries []calendarEntry
+	moved := map[string][]time.Time{}
+	inEvent := false
+	var entry calendarEntry
+	var end, recurrenceID time.Time
+	var duration time.Duration
+	allDay, cancelled := false, false
+	for _, property := range properties {
+		switch {
+		case property.Name == "BEGIN" && strings.EqualFold(property.Value, "VEVENT"):
+			inEvent = true
+			entry, end, recurrenceID, duration, allDay, cancelled = calendarEntry{}, time.Time{}, time.Time{}, 0, false, false
+		case property.Name == "END" && strings.EqualFold(property.Value, "VEVENT"):
+			inEvent = false
+			// Moved (or cancelled) occurrences replace the original ones
+			if !recurrenceID.IsZero() {
+				moved[entry.UID] = append(moved[entry.UID], recurrenceID)
+				entry.Rule = nil
+			}
+			if allDay || cancelled || entry.Start.IsZero() {
+				continue
+			}
+			if end.IsZero() {
+				end = entry.Start.Add(duration)
+			}
+			entry.Length = end.Sub(entry.Start)
+			entries = append(entries, entry)
+		case !inEvent:
+			continue
+		case property.Name == "UID":
+			entry.UID = property.Value
+		case property.Name == "SUMMARY":
+			entry.Summary = icsUnescaper.Replace(property.Value)
+		case property.Name == "STATUS":
+			cancelled = strings.EqualFold(property.Value, "CANCELLED")
+		case property.Name == "DTSTART":
+			entry.Start, allDay, err = parseICSTime(property, zones)
+		case property.Name == "DTEND":
+			end, _, err = parseICSTime(property, zones)
+		case property.Name == "DURATION":
+			duration, err = parseICSDuration(property.Value)
+		case property.Name == "RECURRENCE-ID":
+			recurrenceID, _, err = parseICSTime(property, zones)
+		case property.Name == "EXDATE":
+			for _, value := range strings.Split(property.Value, ",") {
+				exdate, date, exErr := parseICSTime(contentLine{Name: property.Name, Params: property.Params, Value: value}, zones)
+				if exErr != nil {
+					err = exErr
+					break
+				}
+				if date {
+					entry.ExcludedDays = append(entry.ExcludedDays, exdate.Format("20060102"))
+				} else {
+					entry.Excluded = append(entry.Excluded, exdate)
+				}
+			}
+		case property.Name == "RRULE":
+			// Unsupported rules only keep the first occurrence
+			if rec, err := parseRecurrence(property.Value); err == nil {
+				entry.Rule = &rec
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Exclude the moved occurrences from the recurring events
+	for i := range entries {
+		if entries[i].Rule != nil {
+			entries[i].Excluded = append(entries[i].Excluded, moved[entries[i].UID]...)
+		}
+	}
+	return entries, nil
+}
+
+// plotEvents plots a legend listing the upcoming events (those not ended at the
+// given time) with their start in the time of each location.
+func plotEvents(plt Plotter, cfg Config, events []event, locations []locationContainer, t time.Time) {
+	symbol := cfg.Style.EventSymbol
+	if symbol == "" {
+		symbol = EventSymbolDefault
+	}
+	for _, e := range events {
+		if !e.End.After(t) && !e.Start.Equal(t) {
+			continue
+		}
+		times := make([]string, len(locations))
+		for i, location := range locations {
+			times[i] = abbreviate(location) + " " + formatHeader(cfg, e.Start.In(location.location))
+		}
+		plt.PlotString(ContextEvent, symbol)
+		plt.PlotLine(ContextNormal, truncate(" "+e.Summary+": "+strings.Join(times, " · "), max(0, plt.TerminalWidth-textWidth(symbol))))
+	}
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Define info columns
+const (
+	// ColumnName shows the name of the location.
+	ColumnName = "name"
+	// ColumnTime shows the time at the location (see formats).
+	ColumnTime = "time"
+	// ColumnAbbreviation shows the abbreviation of the zone (e.g. CET or EDT).
+	ColumnAbbreviation = "abbreviation"
+	// ColumnOffset shows the offset to UTC (e.g. +05:30).
+	ColumnOffset = "offset"
+	// ColumnRelative shows the offset relative to the local timezone (e.g.
+	// -6h).
+	ColumnRelative = "relative"
+	// ColumnDay shows the day relative to the local timezone (e.g. +1d), if
+	// it differs.
+	ColumnDay = "day"
+	// ColumnTZ shows the IANA timezone identifier (e.g. America/New_York).
+	ColumnTZ = "tz"
+)
+
+// DefaultColumns are the info columns shown, if none are configured.
+var DefaultColumns = []string{ColumnName, ColumnTime}
+
+// checkColumns checks if all given info columns are valid.
+func checkColumns(columns []string) error {
+	for _, column := range columns {
+		switch column {
+		case ColumnName, ColumnTime, ColumnAbbreviation, ColumnOffset, ColumnRelative, ColumnDay, ColumnTZ:
+		default:
+			return fmt.Errorf("invalid column: %s", column)
+		}
+	}
+	return nil
+}
+
+// parseColumns parses a comma-separated list of info columns.
+func parseColumns(columns string) ([]string, error) {
+	var columnList []string
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		// Skip empty columns
+		if column == "" {
+			continue
+		}
+		columnList = append(columnList, column)
+	}
+	return columnList, checkColumns(columnList)
+}
+
+// formatOffsetDifference formats the difference between two UTC offsets (in
+// seconds) in hours (e.g. +5h30m or -6h).
+func formatOffsetDifference(seconds int) string {
+	if seconds == 0 {
+		return "±0h"
+	}
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	hours, minutes := seconds/3600, seconds%3600/60
+	if minutes == 0 {
+		return fmt.Sprintf("%s%dh", sign, hours)
+	}
+	return fmt.Sprintf("%s%dh%02dm", sign, hours, minutes)
+}
+
+// formatDayDifference formats the difference of the calendar days of two
+// times followed by the given unit (e.g. +1d), or returns an empty string, if
+// they are on the same day.
+func formatDayDifference(t, local time.Time, unit string) string {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	localDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(day.Sub(localDay).Hours() / 24)
+	if days == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+d", days) + unit
+}
+
+// columnValue returns the value of the given info column for the location at
+// the given time.
+func columnValue(cfg Config, column string, location locationContainer, t time.Time) string {
+	tzTime := t.In(location.location)
+	localTime := t.In(time.Local)
+	switch column {
+	case ColumnName:
+		return location.description
+	case ColumnTime:
+		if !cfg.Range.IsZero() {
+			// Show the end of the requested range too
+			return formatInfo(cfg, tzTime) + "-" + formatRangeEnd(cfg, cfg.Range, location.location)
+		}
+		return formatInfo(cfg, tzTime)
+	case ColumnAbbreviation:
+		return tzTime.Format("MST")
+	case ColumnOffset:
+		return tzTime.Format("-07:00")
+	case ColumnRelative:
+		_, offset := tzTime.Zone()
+		_, localOffset := localTime.Zone()
+		return formatOffsetDifference(offset - localOffset)
+	case ColumnDay:
+		return formatDayDifference(tzTime, localTime, "d")
+	case ColumnTZ:
+		return location.location.String()
+	default:
+		panic(fmt.Sprintf("invalid column: %s", column))
+	}
+}
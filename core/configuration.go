@@ -25,16 +25,66 @@ type Config struct {
 	// Style defines the style of the timezone plot.
 	Style Style `json:"style"`
 
+	// View defines whether to plot the day around the time, the team or the
+	// week containing it (given per invocation, not stored).
+	View string `json:"-"`
+	// Layout defines whether locations are plotted as horizontal bars or as
+	// vertical columns.
+	Layout string `json:"layout"`
+
 	// Indicates whether to plot tics on the time axis.
 	Tics bool `json:"tics"`
-	// Indicates whether to stretch across the terminal width at cost of
-	// accuracy.
+	// Indicates whether to stretch across the terminal width (slots may not
+	// align with full hours then).
 	Stretch bool `json:"stretch"`
 	// Inline indicates whether location and time info will be plotted on one
 	// line with the bars.
 	Inline bool `json:"inline"`
 	// Indicates whether to use the 24-hour clock.
 	Hours12 bool `json:"hours12"`
+	// Defines the precision of the clock shown (and the refresh rate of live
+	// mode).
+	Precision string `json:"precision"`
+	// Formats defines custom formats for the times shown.
+	Formats TimeFormats `json:"formats"`
+	// Locale defines the language of weekday and month names (e.g. "de",
+	// "pt-BR" or "ja").
+	Locale string `json:"locale"`
+	// Columns defines the info columns shown per location (in order).
+	Columns []string `json:"columns"`
+
+	// Calendars defines iCalendar files (.ics) whose events are marked in the
+	// bars.
+	Calendars []string `json:"calendars"`
+	// Roster is the file describing the people of the team (JSON or CSV, the
+	// config directory's roster.json or roster.csv, if empty).
+	Roster string `json:"roster"`
+	// RosterTags limits the roster to people with any of the tags (given per
+	// invocation, not stored).
+	RosterTags []string `json:"-"`
+	// Line defines the compact one-line output.
+	Line LineFormat `json:"line"`
+	// Output defines whether to plot or to print a single line (given per
+	// invocation, not stored).
+	Output string `json:"-"`
+	// StatusBar defines the status bar to stream updates for (given per
+	// invocation, not stored).
+	StatusBar string `json:"-"`
+	// Export defines the export of the requested time (given per invocation,
+	// not stored).
+	Export ExportOptions `json:"-"`
+	// Import defines how to import locations or people (given per
+	// invocation, not stored).
+	Import ImportOptions `json:"-"`
+	// ServeAddr is the address to serve the API and page at (given per
+	// invocation, not stored).
+	ServeAddr string `json:"-"`
+	// Marks are the requested times marked in the bars, if several or labeled
+	// ones are given (given per invocation, not stored).
+	Marks []Mark `json:"-"`
+	// Range is the requested time range highlighted in the bars, if any
+	// (given per invocation, not stored).
+	Range TimeRange `json:"-"`
 
 	// Indicates whether to continuously update.
 	Live bool `json:"live"`
@@ -52,6 +102,37 @@ type Location struct {
 	Name string
 	// Machine-readable timezone name.
 	TZ string
+	// Short name of the location (optional, used in the one-line output).
+	Abbreviation string `json:",omitempty"`
+	// Latitude of the location (optional, used for sun-based segmentation).
+	Latitude *float64 `json:",omitempty"`
+	// Longitude of the location (optional, used for sun-based segmentation).
+	Longitude *float64 `json:",omitempty"`
+}
+
+// TimeFormats defines custom formats for the times shown. Each format is either
+// a Go layout (e.g. "15:04") or a strftime-style format (e.g. "%H:%M"), if it
+// contains a '%'. Empty formats fall back to the default ones.
+type TimeFormats struct {
+	// Info is the format of the time shown per location.
+	Info string `json:"info"`
+	// Header is the format of the time shown in the header.
+	Header string `json:"header"`
+	// Tics is the format of the tic labels.
+	Tics string `json:"tics"`
+}
+
+// LineFormat defines the compact one-line output.
+type LineFormat struct {
+	// Template is the format of a location with the placeholders {name},
+	// {abbr}, {time}, {zone}, {offset} and {indicator}.
+	Template string `json:"template"`
+	// Separator is placed between the locations.
+	Separator string `json:"separator"`
+	// Indicators defines how the day segments are indicated.
+	Indicators string `json:"indicators"`
+	// Local indicates whether to include the local timezone.
+	Local bool `json:"local"`
 }
 
 type Style struct {
@@ -65,25 +146,36 @@ type Style struct {
 	DaySegmentation DaySegmentation `json:"day_segments"`
 	// Defines the colors to be used in the plot.
 	Coloring PlotColors `json:"coloring"`
+	// Defines how the bars are colored (by day segment or as gradient).
+	ColorMode string `json:"color_mode"`
+	// Defines how the change of the day (midnight) is marked in the bars.
+	Midnight string `json:"midnight"`
+	// Defines the symbol marking midnight.
+	MidnightSymbol string `json:"midnight_symbol,omitempty"`
+	// Defines the symbol marking calendar events.
+	EventSymbol string `json:"event_symbol,omitempty"`
 }
 
 // DaySegmentation defines how to segment the day.
 type DaySegmentation struct {
-	// MorningHour is the hour at which the morning starts.
-	MorningHour int `json:"morning"`
-	// DayHour is the hour at which the day starts (basically business hours).
-	DayHour int `json:"day"`
-	// EveningHour is the hour at which the evening starts.
-	EveningHour int `json:"evening"`
-	// NightHour is the hour at which the night starts.
-	NightHour int `json:"night"`
+	// Mode defines whether the segments are given by the times below or by
+	// the actual position of the sun.
+	Mode string `json:"mode,omitempty"`
+	// Morning is the time at which the morning starts.
+	Morning DayTime `json:"morning"`
+	// Day is the time at which the day starts (basically business hours).
+	Day DayTime `json:"day"`
+	// Evening is the time at which the evening starts.
+	Evening DayTime `json:"evening"`
+	// Night is the time at which the night starts.
+	Night DayTime `json:"night"`
 }
 
 // TimeSymbol defines a symbol to be used from a start time until another symbol
 // is reached.
 type TimeSymbol struct {
 	// Start of the time range.
-	Start int
+	Start DayTime
 	// Symbol to be used for the time.
 	Symbol string
 }
@@ -98,6 +190,10 @@ type PlotColors struct {
 	StaticColorEvening string
 	// StaticColorNight is the color to use for the night segment.
 	StaticColorNight string
+	// StaticColorMidnight is the color to use for the midnight marker.
+	StaticColorMidnight string
+	// StaticColorEvent is the color to use for calendar events.
+	StaticColorEvent string
 	// StaticColorForeground is the color to use for the foreground.
 	StaticColorForeground string
 
@@ -109,10 +205,18 @@ type PlotColors struct {
 	DynamicColorEvening string
 	// DynamicColorNight is the color to use for the morning segment (in live mode).
 	DynamicColorNight string
+	// DynamicColorMidnight is the color to use for the midnight marker (in live mode).
+	DynamicColorMidnight string
+	// DynamicColorEvent is the color to use for calendar events (in live mode).
+	DynamicColorEvent string
 	// DynamicColorForeground is the color to use for the foreground (in live mode).
 	DynamicColorForeground string
 	// DynamicColorBackground is the color to use for the background (in live mode).
 	DynamicColorBackground string
+
+	// GradientPalette defines the hex colors (from night to day) to
+	// interpolate in gradient color mode (for both modes).
+	GradientPalette []string `json:",omitempty"`
 }
 
 // DefaultConfig configuration generator.
@@ -120,13 +224,13 @@ func DefaultConfig() Config {
 	tzs := []Location{}
 	// Add some default locations
 	ny, _ := time.LoadLocation("America/New_York")
-	tzs = append(tzs, Location{"New York", ny.String()})
+	tzs = append(tzs, Location{Name: "New York", TZ: ny.String()})
 	london, _ := time.LoadLocation("Europe/Berlin")
-	tzs = append(tzs, Location{"Berlin", london.String()})
+	tzs = append(tzs, Location{Name: "Berlin", TZ: london.String()})
 	shanghai, _ := time.LoadLocation("Asia/Shanghai")
-	tzs = append(tzs, Location{"Shanghai", shanghai.String()})
+	tzs = append(tzs, Location{Name: "Shanghai", TZ: shanghai.String()})
 	sydney, _ := time.LoadLocation("Australia/Sydney")
-	tzs = append(tzs, Location{"Sydney", sydney.String()})
+	tzs = append(tzs, Location{Name: "Sydney", TZ: sydney.String()})
 	// Return default configuration
 	return Config{
 		ConfigVersion: ConfigVersion,
@@ -134,35 +238,50 @@ func DefaultConfig() Config {
 		Style: Style{
 			Symbols: SymbolModeDefault,
 			CustomSymbols: []TimeSymbol{
-				{6, "▓"},
-				{8, "█"},
-				{18, "▓"},
-				{22, "░"},
+				{NewDayTime(6, 0), "▓"},
+				{NewDayTime(8, 0), "█"},
+				{NewDayTime(18, 0), "▓"},
+				{NewDayTime(22, 0), "░"},
 			},
 			Colorize: false,
 			DaySegmentation: DaySegmentation{
-				MorningHour: 6,
-				DayHour:     8,
-				EveningHour: 18,
-				NightHour:   22,
+				Morning: NewDayTime(6, 0),
+				Day:     NewDayTime(8, 0),
+				Evening: NewDayTime(18, 0),
+				Night:   NewDayTime(22, 0),
 			},
 			Coloring: PlotColors{
 				StaticColorMorning:     "red",
 				StaticColorDay:         "yellow",
 				StaticColorEvening:     "red",
 				StaticColorNight:       "blue",
+				StaticColorMidnight:    "magenta",
+				StaticColorEvent:       "green",
 				StaticColorForeground:  "", // don't override terminal foreground color
 				DynamicColorMorning:    "red",
 				DynamicColorDay:        "yellow",
 				DynamicColorEvening:    "red",
 				DynamicColorNight:      "blue",
+				DynamicColorMidnight:   "magenta",
+				DynamicColorEvent:      "green",
 				DynamicColorForeground: "", // don't override foreground color
 				DynamicColorBackground: "", // don't override background color
 			},
+			ColorMode: ColorModeDefault,
+			Midnight:  MidnightModeDefault,
+		},
+		View:      ViewDefault,
+		Layout:    LayoutDefault,
+		Tics:      false,
+		Stretch:   true,
+		Inline:    true,
+		Precision: PrecisionDefault,
+		Columns:   append([]string{}, DefaultColumns...),
+		Line: LineFormat{
+			Template:   LineTemplateDefault,
+			Separator:  LineSeparatorDefault,
+			Indicators: IndicatorsDefault,
 		},
-		Tics:    false,
-		Stretch: true,
-		Inline:  true,
 	}
 }
 
@@ -232,5 +351,52 @@ func (c *Config) Save() error {
 // validate validates the configuration.
 func (c Config) validate() error {
 	// Check whether symbol configuration is valid
-	return checkSymbolConfig(c.Style)
+	if err := checkSymbolConfig(c.Style); err != nil {
+		return err
+	}
+	// Check whether segmentation is valid
+	if err := checkSegmentationMode(c.Style.DaySegmentation.Mode); err != nil {
+		return err
+	}
+	for _, l := range c.Timezones {
+		if (l.Latitude == nil) != (l.Longitude == nil) {
+			return fmt.Errorf("location %s needs both latitude and longitude", l.Name)
+		}
+		if coords, ok := resolveCoordinates(l); ok {
+			if err := checkCoordinates(coords); err != nil {
+				return err
+			}
+		}
+	}
+	// Check whether view is valid
+	if err := checkView(c.View); err != nil {
+		return err
+	}
+	// Check whether layout is valid
+	if err := checkLayout(c.Layout); err != nil {
+		return err
+	}
+	// Check whether clock precision is valid
+	if err := checkPrecision(c.Precision); err != nil {
+		return err
+	}
+	// Check whether locale is supported
+	if err := checkLocale(c.Locale); err != nil {
+		return err
+	}
+	// Check whether info columns are valid
+	if err := checkColumns(c.Columns); err != nil {
+		return err
+	}
+	// Check whether one-line output indicators are valid
+	if err := checkIndicators(c.Line.Indicators); err != nil {
+		return err
+	}
+	// Check whether custom time formats are valid
+	for _, format := range []string{c.Formats.Info, c.Formats.Header, c.Formats.Tics} {
+		if err := checkTimeFormat(format); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinutesPerDay is the number of minutes in a day.
+const MinutesPerDay = 24 * 60
+
+// DayTime is a time of the day in minutes since midnight. In the configuration
+// it is given either as full hour (e.g. 8) or as "HH:MM" (e.g. "08:30").
+type DayTime int
+
+// NewDayTime creates a time of the day from the given hour and minute.
+func NewDayTime(hour, minute int) DayTime {
+	return DayTime(hour*60 + minute)
+}
+
+// dayTimeOf returns the time of the day of the given time (in its location).
+func dayTimeOf(t time.Time) DayTime {
+	return NewDayTime(t.Hour(), t.Minute())
+}
+
+// String returns the time of the day as "HH:MM".
+func (d DayTime) String() string {
+	return fmt.Sprintf("%02d:%02d", int(d)/60, int(d)%60)
+}
+
+// MarshalJSON marshals full hours as numbers and all others as "HH:MM".
+func (d DayTime) MarshalJSON() ([]byte, error) {
+	if d%60 == 0 {
+		return json.Marshal(int(d) / 60)
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON unmarshals a full hour number or a "HH:MM" string.
+func (d *DayTime) UnmarshalJSON(data []byte) error {
+	// Handle full hours
+	var hour int
+	if err := json.Unmarshal(data, &hour); err == nil {
+		if hour < 0 || hour > 23 {
+			return fmt.Errorf("invalid time of day: %d (hour must be within 0-23)", hour)
+		}
+		*d = NewDayTime(hour, 0)
+		return nil
+	}
+	// Handle "HH:MM"
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid time of day: %s (should be hour or \"HH:MM\")", string(data))
+	}
+	dt, err := parseDayTime(s)
+	if err != nil {
+		return err
+	}
+	*d = dt
+	return nil
+}
+
+// parseDayTime parses a time of the day given as "HH:MM" or full hour "HH".
+func parseDayTime(s string) (DayTime, error) {
+	hourPart, minutePart, found := strings.Cut(strings.TrimSpace(s), ":")
+	hour, err := strconv.Atoi(hourPart)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day: %s (should be \"HH:MM\")", s)
+	}
+	minute := 0
+	if found {
+		minute, err = strconv.Atoi(minutePart)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time of day: %s (should be \"HH:MM\")", s)
+		}
+	}
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid time of day: %s (out of range)", s)
+	}
+	return NewDayTime(hour, minute), nil
+}
@@ -0,0 +1,245 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Define export formats
+const (
+	// ExportICS exports the time as iCalendar event (VEVENT).
+	ExportICS = "ics"
+	// ExportText exports the time as text listing it in every location.
+	ExportText = "text"
+)
+
+// Define export defaults
+const (
+	// ExportDurationDefault is the default duration of exported events.
+	ExportDurationDefault = time.Hour
+	// ExportTitleDefault is the default title of exported events.
+	ExportTitleDefault = "Meeting"
+)
+
+// ExportOptions defines the export of the requested time.
+type ExportOptions struct {
+	// Format is the export format (none, if empty).
+	Format string
+	// Duration of the exported event.
+	Duration time.Duration
+	// Title of the exported event.
+	Title string
+}
+
+// checkExport checks if the given export format is valid (empty refers to no
+// export).
+func checkExport(format string) error {
+	switch format {
+	case "", ExportICS, ExportText:
+		return nil
+	default:
+		return fmt.Errorf("invalid export format: %s", format)
+	}
+}
+
+// formatDuration formats a duration in hours and minutes (e.g. 1h30m or 45m).
+func formatDuration(d time.Duration) string {
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	}
+}
+
+// icsEscaper escapes text values of iCalendar files.
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// icsZone returns the IANA timezone of the time, if any (fixed zones, e.g.
+// offsets and abbreviations, and the local one are written in UTC instead).
+func icsZone(t time.Time) (*time.Location, bool) {
+	loc := t.Location()
+	if loc == time.Local || loc == time.UTC {
+		return nil, false
+	}
+	iana, err := loadLocation(loc.String())
+	if err != nil {
+		return nil, false
+	}
+	// Guard against fixed zones named like IANA ones (e.g. EST)
+	_, offset := t.Zone()
+	_, ianaOffset := t.In(iana).Zone()
+	return iana, offset == ianaOffset
+}
+
+// formatICSTime formats the time as iCalendar date-time property (using its
+// zone as TZID, if it is an IANA one, and UTC otherwise).
+func formatICSTime(name string, t time.Time) string {
+	loc, ok := icsZone(t)
+	if !ok {
+		return name + ":" + t.UTC().Format("20060102T150405Z")
+	}
+	return name + ";TZID=" + loc.String() + ":" + t.In(loc).Format("20060102T150405")
+}
+
+// formatICSOffset formats the UTC offset (in seconds) as iCalendar offset
+// (e.g. +0530).
+func formatICSOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// icsTimezone returns the lines of the VTIMEZONE component of the zone
+// defining the observances (standard or daylight time) in effect at the
+// given times.
+func icsTimezone(loc *time.Location, times ...time.Time) []string {
+	lines := []string{"BEGIN:VTIMEZONE", "TZID:" + loc.String()}
+	seen := map[int64]bool{}
+	for _, t := range times {
+		t = t.In(loc)
+		start, _ := t.ZoneBounds()
+		if seen[start.Unix()] {
+			continue
+		}
+		seen[start.Unix()] = true
+		name, offset := t.Zone()
+		// Observances start at the local time of the previous offset (zones
+		// without transitions are in effect since the epoch)
+		from, onset := offset, time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+		if !start.IsZero() {
+			_, from = start.Add(-time.Second).Zone()
+			onset = start.In(time.FixedZone("", from))
+		}
+		kind := "STANDARD"
+		if t.IsDST() {
+			kind = "DAYLIGHT"
+		}
+		lines = append(lines,
+			"BEGIN:"+kind,
+			"DTSTART:"+onset.Format("20060102T150405"),
+			"TZOFFSETFROM:"+formatICSOffset(from),
+			"TZOFFSETTO:"+formatICSOffset(offset),
+			"TZNAME:"+name,
+			"END:"+kind,
+		)
+	}
+	return append(lines, "END:VTIMEZONE")
+}
+
+// maxICSLineLength is the maximum length of content lines in octets (longer
+// ones are folded).
+const maxICSLineLength = 75
+
+// foldICSLine folds the content line into lines of at most 75 octets (not
+// splitting characters), continued lines start with a space.
+func foldICSLine(line string) string {
+	sb := strings.Builder{}
+	length := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if length+size > maxICSLineLength {
+			sb.WriteString("\r\n ")
+			length = 1
+		}
+		sb.WriteRune(r)
+		length += size
+	}
+	return sb.String()
+}
+
+// writeICS writes the event as iCalendar file.
+func writeICS(w io.Writer, c Config, t, stamp time.Time) error {
+	// Derive a stable identifier from the event
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(c.Export.Title + c.Export.Duration.String()))
+	uid := fmt.Sprintf("%s-%08x@gotz", t.UTC().Format("20060102T150405Z"), h.Sum32())
+	end := t.Add(c.Export.Duration)
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//gotz//gotz//EN",
+	}
+	// Define the timezone referenced by the event, if any
+	if loc, ok := icsZone(t); ok {
+		lines = append(lines, icsTimezone(loc, t, end)...)
+	}
+	lines = append(lines,
+		"BEGIN:VEVENT",
+		"UID:"+uid,
+		"DTSTAMP:"+stamp.UTC().Format("20060102T150405Z"),
+		formatICSTime("DTSTART", t),
+		formatICSTime("DTEND", end),
+		"SUMMARY:"+icsEscaper.Replace(c.Export.Title),
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	for _, line := range lines {
+		if _, err := fmt.Fprint(w, foldICSLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeText writes the event as text listing it in every configured location.
+func writeText(w io.Writer, c Config, t time.Time) error {
+	_, locations, err := createTimeInfos(c, t)
+	if err != nil {
+		return err
+	}
+	l := getLocale(c.Locale)
+	// Describe the event in the requested zone
+	head := c.Export.Title + " - " + formatDay(l, t) + " " + formatHeader(c, t) + " (" + t.Location().String() + ")"
+	if c.Export.Duration > 0 {
+		head += ", " + formatDuration(c.Export.Duration)
+	}
+	// List the event in every location (marking different days)
+	var parts []string
+	for _, location := range locations {
+		if location.location == time.Local {
+			continue
+		}
+		start, end := t.In(location.location), t.Add(c.Export.Duration).In(location.location)
+		part := formatHeader(c, start)
+		if c.Export.Duration > 0 {
+			part += "-" + formatHeader(c, end)
+		}
+		part += " " + location.description
+		if start.Day() != t.Day() {
+			part += " (" + l.shortDays[start.Weekday()] + ")"
+		}
+		parts = append(parts, part)
+	}
+	_, err = fmt.Fprintln(w, head+"\n"+strings.Join(parts, " / "))
+	return err
+}
+
+// WriteExport writes the export of the given time in the configured format.
+// The stamp is the time of creation.
+func WriteExport(w io.Writer, c Config, t, stamp time.Time) error {
+	if c.Export.Title == "" {
+		c.Export.Title = ExportTitleDefault
+	}
+	// Export the requested range, if any (instead of the configured duration)
+	if !c.Range.IsZero() {
+		t, c.Export.Duration = c.Range.Start, c.Range.End.Sub(c.Range.Start)
+	}
+	switch c.Export.Format {
+	case ExportICS:
+		return writeICS(w, c, t, stamp)
+	case ExportText:
+		return writeText(w, c, t)
+	default:
+		return fmt.Errorf("invalid export format: %s", c.Export.Format)
+	}
+}
@@ -2,8 +2,10 @@ package core
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
@@ -17,21 +19,25 @@ const (
 	ContextDay     ContextType = "day"
 	ContextEvening ContextType = "evening"
 	ContextNight   ContextType = "night"
+	// ContextMidnight marks the change of the day.
+	ContextMidnight ContextType = "midnight"
+	// ContextEvent marks calendar events.
+	ContextEvent ContextType = "event"
 )
 
-// getDaySegment returns the day segment for the given hour.
-func getDaySegment(seg DaySegmentation, hour int) ContextType {
+// getDaySegment returns the day segment for the given time of the day.
+func getDaySegment(seg DaySegmentation, t DayTime) ContextType {
 	switch {
-	case hour < seg.MorningHour || hour >= seg.NightHour:
+	case t < seg.Morning || t >= seg.Night:
 		return ContextNight
-	case hour < seg.DayHour:
+	case t < seg.Day:
 		return ContextMorning
-	case hour < seg.EveningHour:
+	case t < seg.Evening:
 		return ContextDay
-	case hour < seg.NightHour:
+	case t < seg.Night:
 		return ContextEvening
 	default:
-		panic(fmt.Sprintf("invalid hour: %d", hour))
+		panic(fmt.Sprintf("invalid time of day: %s", t))
 	}
 }
 
@@ -92,6 +98,12 @@ func getDynamicColorMap(sty PlotColors) map[ContextType]tcell.Style {
 	dynamicColorMap[ContextDay] = baseStyle.Foreground(getColor(sty.DynamicColorDay))
 	dynamicColorMap[ContextEvening] = baseStyle.Foreground(getColor(sty.DynamicColorEvening))
 	dynamicColorMap[ContextNight] = baseStyle.Foreground(getColor(sty.DynamicColorNight))
+	dynamicColorMap[ContextMidnight] = baseStyle.Foreground(getColor(sty.DynamicColorMidnight))
+	dynamicColorMap[ContextEvent] = baseStyle.Foreground(getColor(sty.DynamicColorEvent))
+	for ctx, rgb := range gradientColors(sty.GradientPalette) {
+		dynamicColorMap[ctx] = baseStyle.Foreground(tcell.NewRGBColor(int32(rgb[0]), int32(rgb[1]), int32(rgb[2])))
+	}
+	addDynamicRangeColors(dynamicColorMap)
 	return dynamicColorMap
 }
 
@@ -122,9 +134,100 @@ func getStaticColorMap(sty PlotColors) map[ContextType]string {
 	staticColorMap[ContextDay] = getColor(sty.StaticColorDay)
 	staticColorMap[ContextEvening] = getColor(sty.StaticColorEvening)
 	staticColorMap[ContextNight] = getColor(sty.StaticColorNight)
+	staticColorMap[ContextMidnight] = getColor(sty.StaticColorMidnight)
+	staticColorMap[ContextEvent] = getColor(sty.StaticColorEvent)
+	for ctx, rgb := range gradientColors(sty.GradientPalette) {
+		staticColorMap[ctx] = fmt.Sprintf("\u001b[38;2;%d;%d;%dm", rgb[0], rgb[1], rgb[2])
+	}
+	addStaticRangeColors(staticColorMap)
 	return staticColorMap
 }
 
+// Define color modes
+const (
+	// ColorModeSegments colors the bars by day segment.
+	ColorModeSegments = "segments"
+	// ColorModeGradient colors the bars by a gradient interpolated from a
+	// palette based on the time of the day (or the sun's elevation, if the
+	// coordinates are known).
+	ColorModeGradient = "gradient"
+	// ColorModeDefault is the default color mode.
+	ColorModeDefault = ColorModeSegments
+)
+
+// gradientSteps is the number of distinct colors of a gradient.
+const gradientSteps = 32
+
+// DefaultGradientPalette is the default palette for gradient coloring (from
+// night to day).
+var DefaultGradientPalette = []string{"#0B1026", "#2B2F77", "#C0392B", "#F39C12", "#FFE8A3"}
+
+// checkColorMode checks if the given color mode is valid (empty refers to the
+// default).
+func checkColorMode(mode string) error {
+	switch mode {
+	case "", ColorModeSegments, ColorModeGradient:
+		return nil
+	default:
+		return fmt.Errorf("invalid color mode: %s", mode)
+	}
+}
+
+// checkGradientPalette checks if the given gradient palette is valid (empty
+// refers to the default).
+func checkGradientPalette(palette []string) error {
+	if len(palette) == 1 {
+		return fmt.Errorf("gradient palette needs at least two colors")
+	}
+	for _, c := range palette {
+		if !strings.HasPrefix(c, "#") {
+			return fmt.Errorf("invalid gradient color: %s (must be a hex code)", c)
+		}
+		if _, _, _, err := convertHexToRgb(c); err != nil {
+			return fmt.Errorf("invalid gradient color: %s (must be a hex code)", c)
+		}
+	}
+	return nil
+}
+
+// gradientContext returns the context of the gradient step for the given light
+// level (from 0 - night to 1 - day).
+func gradientContext(level float64) ContextType {
+	level = math.Max(0, math.Min(1, level))
+	return ContextType(fmt.Sprintf("gradient-%02d", int(math.Round(level*(gradientSteps-1)))))
+}
+
+// gradientColors returns the colors of all gradient steps (by context)
+// interpolated linearly from the given palette.
+func gradientColors(palette []string) map[ContextType][3]uint8 {
+	if len(palette) == 0 {
+		palette = DefaultGradientPalette
+	}
+	// Convert palette
+	rgbs := make([][3]float64, len(palette))
+	for i, c := range palette {
+		r, g, b, err := convertHexToRgb(strings.ToLower(c))
+		if err != nil {
+			panic(err)
+		}
+		rgbs[i] = [3]float64{float64(r), float64(g), float64(b)}
+	}
+	// Interpolate
+	colors := make(map[ContextType][3]uint8, gradientSteps)
+	for step := 0; step < gradientSteps; step++ {
+		level := float64(step) / (gradientSteps - 1)
+		pos := level * float64(len(rgbs)-1)
+		idx := int(math.Min(math.Floor(pos), float64(len(rgbs)-2)))
+		frac := pos - float64(idx)
+		var rgb [3]uint8
+		for k := range rgb {
+			rgb[k] = uint8(math.Round(rgbs[idx][k] + (rgbs[idx+1][k]-rgbs[idx][k])*frac))
+		}
+		colors[gradientContext(level)] = rgb
+	}
+	return colors
+}
+
 // Define symbol modes
 const (
 	// SymbolModeRectangles uses different kinds of rectangles to represent the
@@ -161,6 +264,45 @@ var (
 	}
 )
 
+// Define midnight modes
+const (
+	// MidnightModeNone does not mark the change of the day.
+	MidnightModeNone = "none"
+	// MidnightModeMarker marks the change of the day with a symbol.
+	MidnightModeMarker = "marker"
+	// MidnightModeLabel marks the change of the day with a symbol followed by
+	// the new weekday.
+	MidnightModeLabel = "label"
+	// MidnightModeDefault is the default midnight mode.
+	MidnightModeDefault = MidnightModeNone
+	// MidnightSymbolDefault is the default symbol marking midnight.
+	MidnightSymbolDefault = "┊"
+)
+
+// checkMidnightMode checks if the given midnight mode is valid (empty refers to
+// the default).
+func checkMidnightMode(mode string) error {
+	switch mode {
+	case "", MidnightModeNone, MidnightModeMarker, MidnightModeLabel:
+		return nil
+	default:
+		return fmt.Errorf("invalid midnight mode: %s", mode)
+	}
+}
+
+// segmentSymbols returns the symbols per day segment for symbol modes that
+// depict the segments (nil for all others).
+func segmentSymbols(mode string) map[ContextType]string {
+	switch mode {
+	case "", SymbolModeRectangles:
+		return RectangleSymbols
+	case SymbolModeSunMoon:
+		return SunMoonSymbols
+	default:
+		return nil
+	}
+}
+
 // checkSymbolMode checks if the given symbol mode is valid.
 func checkSymbolMode(mode string) error {
 	switch mode {
@@ -173,47 +315,63 @@ func checkSymbolMode(mode string) error {
 
 // checkSymbolMode does a small sanity check on the symbol definition.
 func checkSymbolConfig(sty Style) error {
+	if err := checkColorMode(sty.ColorMode); err != nil {
+		return err
+	}
+	if err := checkGradientPalette(sty.Coloring.GradientPalette); err != nil {
+		return err
+	}
+	if err := checkMidnightMode(sty.Midnight); err != nil {
+		return err
+	}
+	if sty.MidnightSymbol != "" && utf8.RuneCountInString(sty.MidnightSymbol) != 1 {
+		return fmt.Errorf("midnight symbol %s is not a single character", sty.MidnightSymbol)
+	}
+	if sty.EventSymbol != "" && utf8.RuneCountInString(sty.EventSymbol) != 1 {
+		return fmt.Errorf("event symbol %s is not a single character", sty.EventSymbol)
+	}
 	if sty.Symbols == SymbolModeCustom {
 		if len(sty.CustomSymbols) <= 0 {
 			return fmt.Errorf("custom symbols not defined")
 		}
-		seenHours := map[int]bool{}
+		seenStarts := map[DayTime]bool{}
 		for _, s := range sty.CustomSymbols {
 			if utf8.RuneCountInString(s.Symbol) != 1 {
 				return fmt.Errorf("custom symbol %s is not a single character", s.Symbol)
 			}
-			if _, ok := seenHours[s.Start]; ok {
-				return fmt.Errorf("duplicate custom symbol for hour %d", s.Start)
+			if _, ok := seenStarts[s.Start]; ok {
+				return fmt.Errorf("duplicate custom symbol for time %s", s.Start)
 			}
-			seenHours[s.Start] = true
+			seenStarts[s.Start] = true
 		}
 	}
 	return nil
 }
 
+// GetSymbols returns the symbols representing each minute of the day.
 func GetSymbols(sty Style) []string {
-	symbols := make([]string, 24)
+	symbols := make([]string, MinutesPerDay)
 	switch sty.Symbols {
 	default:
 		fallthrough
 	case SymbolModeRectangles:
-		for h := range symbols {
-			symbols[h] = RectangleSymbols[getDaySegment(sty.DaySegmentation, h)]
+		for m := range symbols {
+			symbols[m] = RectangleSymbols[getDaySegment(sty.DaySegmentation, DayTime(m))]
 		}
 	case SymbolModeSunMoon:
-		for h := range symbols {
-			symbols[h] = SunMoonSymbols[getDaySegment(sty.DaySegmentation, h)]
+		for m := range symbols {
+			symbols[m] = SunMoonSymbols[getDaySegment(sty.DaySegmentation, DayTime(m))]
 		}
 	case SymbolModeMono:
-		for h := range symbols {
-			symbols[h] = "#"
+		for m := range symbols {
+			symbols[m] = "#"
 		}
 	case SymbolModeBlocks:
-		for h := range symbols {
-			symbols[h] = "█"
+		for m := range symbols {
+			symbols[m] = "█"
 		}
 	case SymbolModeCustom:
-		// Sort custom symbols by hour
+		// Sort custom symbols by start
 		customSymbols := make([]TimeSymbol, len(sty.CustomSymbols))
 		copy(customSymbols, sty.CustomSymbols)
 		sort.Slice(customSymbols, func(i, j int) bool {
@@ -221,13 +379,45 @@ func GetSymbols(sty Style) []string {
 		})
 		// Start with the symbol the previous day ends with
 		currentSym, currentIdx := customSymbols[len(customSymbols)-1].Symbol, -1
-		for h := range symbols {
+		for m := range symbols {
 			// Find the next custom symbol
-			if currentIdx < len(customSymbols)-1 && h == customSymbols[currentIdx+1].Start {
+			if currentIdx < len(customSymbols)-1 && DayTime(m) == customSymbols[currentIdx+1].Start {
 				currentSym, currentIdx = customSymbols[currentIdx+1].Symbol, currentIdx+1
 			}
-			symbols[h] = currentSym
+			symbols[m] = currentSym
 		}
 	}
 	return symbols
 }
+
+// Define clock precisions
+const (
+	// PrecisionMinutes shows the time with minute precision and refreshes
+	// live mode every minute.
+	PrecisionMinutes = "minutes"
+	// PrecisionSeconds shows the time with second precision and refreshes
+	// live mode every second.
+	PrecisionSeconds = "seconds"
+	// PrecisionDefault is the default clock precision.
+	PrecisionDefault = PrecisionMinutes
+)
+
+// checkPrecision checks if the given clock precision is valid (empty refers to
+// the default).
+func checkPrecision(precision string) error {
+	switch precision {
+	case "", PrecisionMinutes, PrecisionSeconds:
+		return nil
+	default:
+		return fmt.Errorf("invalid precision: %s", precision)
+	}
+}
+
+// refreshInterval returns the interval at which the time shown needs to be
+// refreshed for the given clock precision.
+func refreshInterval(precision string) time.Duration {
+	if precision == PrecisionSeconds {
+		return time.Second
+	}
+	return time.Minute
+}
@@ -0,0 +1,381 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Define import targets
+const (
+	// ImportTargetTimezones adds the imported entries to the timezones of the
+	// configuration.
+	ImportTargetTimezones = "timezones"
+	// ImportTargetRoster adds the imported entries to the people of the
+	// roster.
+	ImportTargetRoster = "roster"
+	// ImportTargetDefault is the default import target.
+	ImportTargetDefault = ImportTargetTimezones
+)
+
+// Define import defaults
+const (
+	// ImportNameColumnDefault is the default CSV column of the names.
+	ImportNameColumnDefault = "name"
+	// ImportTZColumnDefault is the default CSV column of the timezones.
+	ImportTZColumnDefault = "tz"
+)
+
+// ImportOptions defines how to import locations or people.
+type ImportOptions struct {
+	// Target defines where to add the imported entries.
+	Target string
+	// NameColumn is the CSV column of the names (header name or 1-based
+	// index).
+	NameColumn string
+	// TZColumn is the CSV column of the timezones (header name or 1-based
+	// index).
+	TZColumn string
+}
+
+// checkImportTarget checks if the given import target is valid (empty refers
+// to the default).
+func checkImportTarget(target string) error {
+	switch target {
+	case "", ImportTargetTimezones, ImportTargetRoster:
+		return nil
+	default:
+		return fmt.Errorf("invalid import target: %s", target)
+	}
+}
+
+// ImportFailure describes an entry that could not be imported.
+type ImportFailure struct {
+	// Source describes the entry (e.g. "line 4" or "card 2").
+	Source string
+	// Reason describes why the entry was not imported.
+	Reason string
+}
+
+// ImportReport summarizes an import.
+type ImportReport struct {
+	// Added are the entries imported.
+	Added []string
+	// Skipped are the entries already present.
+	Skipped []string
+	// Failed are the entries that could not be imported.
+	Failed []ImportFailure
+}
+
+// importRecord is a single entry read for import.
+type importRecord struct {
+	// Source describes the entry (e.g. "line 4" or "card 2").
+	Source string
+	// Name of the location or person.
+	Name string
+	// TZ is the timezone as given.
+	TZ string
+}
+
+// isVCardFile indicates whether the file is a vCard file (by extension).
+func isVCardFile(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".vcf" || ext == ".vcard"
+}
+
+// readImportFile reads the entries of a CSV or vCard file.
+func readImportFile(file string, opts ImportOptions) ([]importRecord, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if isVCardFile(file) {
+		return readVCards(f)
+	}
+	return readImportCSV(f, opts)
+}
+
+// findColumn returns the index of the column given by header name (case
+// insensitive) or 1-based index.
+func findColumn(header []string, column string) (int, error) {
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+	if i, err := strconv.Atoi(column); err == nil && i >= 1 && i <= len(header) {
+		return i - 1, nil
+	}
+	return 0, fmt.Errorf("column not found: %s", column)
+}
+
+// readImportCSV reads the entries of a CSV file (with header).
+func readImportCSV(r io.Reader, opts ImportOptions) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	// Determine columns
+	nameColumn, tzColumn := opts.NameColumn, opts.TZColumn
+	if nameColumn == "" {
+		nameColumn = ImportNameColumnDefault
+	}
+	if tzColumn == "" {
+		tzColumn = ImportTZColumnDefault
+	}
+	nameIndex, err := findColumn(rows[0], nameColumn)
+	if err != nil {
+		return nil, err
+	}
+	tzIndex, err := findColumn(rows[0], tzColumn)
+	if err != nil {
+		return nil, err
+	}
+	// Read entries
+	records := make([]importRecord, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		record := importRecord{Source: fmt.Sprintf("line %d", i+2)}
+		if nameIndex < len(row) {
+			record.Name = strings.TrimSpace(row[nameIndex])
+		}
+		if tzIndex < len(row) {
+			record.TZ = strings.TrimSpace(row[tzIndex])
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readVCards reads the entries of a vCard file (using the formatted name and
+// the TZ field of each card).
+func readVCards(r io.Reader) ([]importRecord, error) {
+	lines, err := readContentLines(r)
+	if err != nil {
+		return nil, err
+	}
+	var records []importRecord
+	var record importRecord
+	for _, line := range lines {
+		switch {
+		case line.Name == "BEGIN" && strings.EqualFold(line.Value, "VCARD"):
+			record = importRecord{Source: fmt.Sprintf("card %d", len(records)+1)}
+		case line.Name == "END" && strings.EqualFold(line.Value, "VCARD"):
+			records = append(records, record)
+		case line.Name == "FN":
+			record.Name = strings.TrimSpace(icsUnescaper.Replace(line.Value))
+		case line.Name == "TZ":
+			record.TZ = strings.TrimSpace(line.Value)
+		}
+	}
+	return records, nil
+}
+
+// utcOffsetPattern matches UTC offsets as given by vCards (e.g. -05:00).
+var utcOffsetPattern = regexp.MustCompile(`^([+-])(\d{2}):?(\d{2})$`)
+
+// resolveImportTZ resolves the timezone given (IANA names or full hour UTC
+// offsets, which are mapped to the Etc zones).
+func resolveImportTZ(tz string) (string, error) {
+	if tz == "" {
+		return "", errors.New("missing timezone")
+	}
+	if m := utcOffsetPattern.FindStringSubmatch(tz); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		if m[3] != "00" {
+			return "", fmt.Errorf("no timezone for offset %s (use an IANA name)", tz)
+		}
+		if hours == 0 {
+			return "UTC", nil
+		}
+		// Etc zones use inverted signs (e.g. Etc/GMT+5 is UTC-05:00)
+		sign := "-"
+		if m[1] == "-" {
+			sign = "+"
+		}
+		tz = fmt.Sprintf("Etc/GMT%s%d", sign, hours)
+	}
+	if !checkTimezoneLocation(tz) {
+		return "", fmt.Errorf("invalid timezone: %s", tz)
+	}
+	return tz, nil
+}
+
+// importEntries validates the records and returns the new ones (neither
+// present nor imported before).
+func importEntries(records []importRecord, present func(name, tz string) bool) ([]importRecord, ImportReport) {
+	var report ImportReport
+	var added []importRecord
+	for _, record := range records {
+		if record.Name == "" {
+			report.Failed = append(report.Failed, ImportFailure{Source: record.Source, Reason: "missing name"})
+			continue
+		}
+		tz, err := resolveImportTZ(record.TZ)
+		if err != nil {
+			report.Failed = append(report.Failed, ImportFailure{Source: record.Source, Reason: record.Name + ": " + err.Error()})
+			continue
+		}
+		record.TZ = tz
+		entry := fmt.Sprintf("%s (%s)", record.Name, tz)
+		duplicate := present(record.Name, tz)
+		for _, a := range added {
+			duplicate = duplicate || (strings.EqualFold(a.Name, record.Name) && a.TZ == tz)
+		}
+		if duplicate {
+			report.Skipped = append(report.Skipped, entry)
+			continue
+		}
+		added = append(added, record)
+		report.Added = append(report.Added, entry)
+	}
+	return added, report
+}
+
+// ImportLocations adds the locations of the given CSV or vCard file to the
+// timezones of the configuration (skipping the ones already present).
+func ImportLocations(c Config, file string) (Config, ImportReport, error) {
+	records, err := readImportFile(file, c.Import)
+	if err != nil {
+		return c, ImportReport{}, fmt.Errorf("error reading %s: %s", file, err)
+	}
+	added, report := importEntries(records, func(name, tz string) bool {
+		for _, l := range c.Timezones {
+			if strings.EqualFold(l.Name, name) && l.TZ == tz {
+				return true
+			}
+		}
+		return false
+	})
+	timezones := append([]Location{}, c.Timezones...)
+	for _, record := range added {
+		timezones = append(timezones, Location{Name: record.Name, TZ: record.TZ})
+	}
+	c.Timezones = timezones
+	return c, report, nil
+}
+
+// ImportPeople adds the people of the given CSV or vCard file to the roster
+// (skipping the ones already present).
+func ImportPeople(roster Roster, c Config, file string) (Roster, ImportReport, error) {
+	records, err := readImportFile(file, c.Import)
+	if err != nil {
+		return roster, ImportReport{}, fmt.Errorf("error reading %s: %s", file, err)
+	}
+	added, report := importEntries(records, func(name, tz string) bool {
+		for _, p := range roster.People {
+			if strings.EqualFold(p.Name, name) && p.TZ == tz {
+				return true
+			}
+		}
+		return false
+	})
+	people := append([]Person{}, roster.People...)
+	for _, record := range added {
+		people = append(people, Person{Name: record.Name, TZ: record.TZ})
+	}
+	roster.People = people
+	return roster, report, nil
+}
+
+// rosterFile returns the configured roster file (or the default one found),
+// if it exists.
+func rosterFile(c Config) string {
+	files := defaultRosterFiles()
+	if c.Roster != "" {
+		files = []string{c.Roster}
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return ""
+}
+
+// saveRoster writes the roster as JSON to its file (the configured one or the
+// default roster.json of the config directory, if none exists yet).
+func saveRoster(c Config, roster Roster) error {
+	file := rosterFile(c)
+	if file == "" {
+		file = c.Roster
+	}
+	if file == "" {
+		file = defaultRosterFiles()[0]
+	}
+	if strings.EqualFold(filepath.Ext(file), ".csv") {
+		return fmt.Errorf("cannot import into CSV roster %s (use a JSON one)", file)
+	}
+	data, err := json.MarshalIndent(roster, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+// Import imports the locations or people of the given CSV or vCard file into
+// the configured target, saves it and prints a report.
+func Import(c Config, file string) error {
+	if file == "" {
+		return errors.New("no file to import given (gotz import <file>)")
+	}
+	var report ImportReport
+	target := c.Import.Target
+	switch target {
+	case ImportTargetRoster:
+		// Start with an empty roster, if none exists yet
+		roster, err := LoadRoster(c)
+		if err != nil {
+			if rosterFile(c) != "" {
+				return err
+			}
+			roster = Roster{}
+		}
+		roster, report, err = ImportPeople(roster, c, file)
+		if err != nil {
+			return err
+		}
+		if err := saveRoster(c, roster); err != nil {
+			return err
+		}
+	default:
+		target = ImportTargetTimezones
+		updated, r, err := ImportLocations(c, file)
+		if err != nil {
+			return err
+		}
+		report = r
+		if err := updated.Save(); err != nil {
+			return err
+		}
+	}
+	// Print report
+	total := len(report.Added) + len(report.Skipped) + len(report.Failed)
+	fmt.Printf("imported %d of %d entries into %s\n", len(report.Added), total, target)
+	for _, entry := range report.Added {
+		fmt.Println("  added:", entry)
+	}
+	for _, entry := range report.Skipped {
+		fmt.Println("  skipped (already present):", entry)
+	}
+	for _, failure := range report.Failed {
+		fmt.Printf("  failed (%s): %s\n", failure.Source, failure.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Define output formats
+const (
+	// OutputPlot plots the bars (or any other view).
+	OutputPlot = "plot"
+	// OutputLine prints a single line with the times of all locations (e.g.
+	// for shell prompts and status bars).
+	OutputLine = "line"
+	// OutputDefault is the default output format.
+	OutputDefault = OutputPlot
+)
+
+// checkOutput checks if the given output format is valid (empty refers to the
+// default).
+func checkOutput(output string) error {
+	switch output {
+	case "", OutputPlot, OutputLine:
+		return nil
+	default:
+		return fmt.Errorf("invalid format: %s", output)
+	}
+}
+
+// Define segment indicators of the one-line output
+const (
+	// IndicatorsNone shows no segment indicators.
+	IndicatorsNone = "none"
+	// IndicatorsColor colors the locations by their day segment.
+	IndicatorsColor = "color"
+	// IndicatorsEmoji shows an emoji per day segment.
+	IndicatorsEmoji = "emoji"
+	// IndicatorsDefault is the default segment indicator mode.
+	IndicatorsDefault = IndicatorsNone
+)
+
+// checkIndicators checks if the given segment indicator mode is valid (empty
+// refers to the default).
+func checkIndicators(mode string) error {
+	switch mode {
+	case "", IndicatorsNone, IndicatorsColor, IndicatorsEmoji:
+		return nil
+	default:
+		return fmt.Errorf("invalid indicators: %s", mode)
+	}
+}
+
+// Define defaults of the one-line output
+const (
+	// LineTemplateDefault is the default template of a location.
+	LineTemplateDefault = "{indicator}{abbr} {time}"
+	// LineSeparatorDefault is the default separator between locations.
+	LineSeparatorDefault = " · "
+)
+
+// segmentEmojis maps the day segments to their emoji indicator.
+var segmentEmojis = map[ContextType]string{
+	ContextMorning: "🌅",
+	ContextDay:     "☀️",
+	ContextEvening: "🌇",
+	ContextNight:   "🌙",
+}
+
+// colorizeOutput indicates whether to colorize the output (colored segment
+// indicators of the one-line output are colored, even if colors are disabled
+// otherwise).
+func colorizeOutput(c Config) bool {
+	return c.Style.Colorize || (c.Output == OutputLine && c.Line.Indicators == IndicatorsColor)
+}
+
+// abbreviate returns the short name of the location, i.e., the configured
+// abbreviation or one derived from the name (the initials of multiple words,
+// e.g. NY, or the first three letters, e.g. BER).
+func abbreviate(location locationContainer) string {
+	if location.abbreviation != "" {
+		return location.abbreviation
+	}
+	words := strings.Fields(location.description)
+	if len(words) > 1 {
+		initials := ""
+		for _, word := range words {
+			initials += string([]rune(word)[:1])
+		}
+		return strings.ToUpper(initials)
+	}
+	runes := []rune(location.description)
+	if len(runes) > 3 {
+		runes = runes[:3]
+	}
+	return strings.Map(unicode.ToUpper, string(runes))
+}
+
+// plotLine plots the times of all locations in a single line.
+func plotLine(plt Plotter, cfg Config, t time.Time) error {
+	// Get locations (sorted)
+	_, locations, err := createTimeInfos(cfg, t)
+	if err != nil {
+		return err
+	}
+	template := cfg.Line.Template
+	if template == "" {
+		template = LineTemplateDefault
+	}
+	separator := cfg.Line.Separator
+	if separator == "" {
+		separator = LineSeparatorDefault
+	}
+
+	first := true
+	for _, location := range locations {
+		// Skip local timezone, if not desired
+		if location.location == time.Local && !cfg.Line.Local {
+			continue
+		}
+		tzTime := t.In(location.location)
+		// Determine segment indicator
+		indicator := ""
+		context := ContextNormal
+		switch cfg.Line.Indicators {
+		case IndicatorsEmoji:
+			indicator = segmentEmojis[getLocationSegment(cfg, location, tzTime)] + " "
+		case IndicatorsColor:
+			_, context = getSlotStyle(plt, cfg, location, t)
+		}
+		// Fill in template
+		entry := strings.NewReplacer(
+			"{name}", location.description,
+			"{abbr}", abbreviate(location),
+			"{time}", formatHeader(cfg, tzTime),
+			"{zone}", tzTime.Format("MST"),
+			"{offset}", tzTime.Format("-07:00"),
+			"{indicator}", indicator,
+		).Replace(template)
+		if !first {
+			plt.PlotString(ContextNormal, separator)
+		}
+		plt.PlotString(context, entry)
+		first = false
+	}
+	plt.PlotLine(ContextNormal)
+
+	return nil
+}
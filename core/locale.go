@@ -0,0 +1,266 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// locale defines the names and defaults of a language used for formatting
+// times.
+type locale struct {
+	// Full names of the weekdays (starting with Sunday).
+	days [7]string
+	// Abbreviated names of the weekdays (starting with Sunday).
+	shortDays [7]string
+	// Full names of the months.
+	months [12]string
+	// Abbreviated names of the months.
+	shortMonths [12]string
+	// Markers for before and after noon.
+	am, pm string
+	// Indicates whether the 12-hour clock is commonly used.
+	hours12 bool
+}
+
+// LocaleDefault is the default locale (English names).
+const LocaleDefault = "en"
+
+// locales contains the embedded translation tables of all supported locales.
+var locales = map[string]locale{
+	"en": {
+		days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		am:          "AM",
+		pm:          "PM",
+		hours12:     true,
+	},
+	"en-gb": {
+		days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		am:          "AM",
+		pm:          "PM",
+		hours12:     false,
+	},
+	"de": {
+		days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		am:          "AM",
+		pm:          "PM",
+		hours12:     false,
+	},
+	"fr": {
+		days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDays:   [7]string{"dim", "lun", "mar", "mer", "jeu", "ven", "sam"},
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv", "févr", "mars", "avr", "mai", "juin", "juil", "août", "sept", "oct", "nov", "déc"},
+		am:          "AM",
+		pm:          "PM",
+		hours12:     false,
+	},
+	"es": {
+		days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+		am:          "a. m.",
+		pm:          "p. m.",
+		hours12:     false,
+	},
+	"it": {
+		days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		am:          "AM",
+		pm:          "PM",
+		hours12:     false,
+	},
+	"nl": {
+		days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		shortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		shortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		am:          "a.m.",
+		pm:          "p.m.",
+		hours12:     false,
+	},
+	"pt": {
+		days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		shortDays:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		shortMonths: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		am:          "AM",
+		pm:          "PM",
+		hours12:     false,
+	},
+	"sv": {
+		days:        [7]string{"söndag", "måndag", "tisdag", "onsdag", "torsdag", "fredag", "lördag"},
+		shortDays:   [7]string{"sön", "mån", "tis", "ons", "tors", "fre", "lör"},
+		months:      [12]string{"januari", "februari", "mars", "april", "maj", "juni", "juli", "augusti", "september", "oktober", "november", "december"},
+		shortMonths: [12]string{"jan", "feb", "mars", "apr", "maj", "juni", "juli", "aug", "sep", "okt", "nov", "dec"},
+		am:          "fm",
+		pm:          "em",
+		hours12:     false,
+	},
+	"pl": {
+		days:        [7]string{"niedziela", "poniedziałek", "wtorek", "środa", "czwartek", "piątek", "sobota"},
+		shortDays:   [7]string{"niedz.", "pon.", "wt.", "śr.", "czw.", "pt.", "sob."},
+		months:      [12]string{"styczeń", "luty", "marzec", "kwiecień", "maj", "czerwiec", "lipiec", "sierpień", "wrzesień", "październik", "listopad", "grudzień"},
+		shortMonths: [12]string{"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"},
+		am:          "AM",
+		pm:          "PM",
+		hours12:     false,
+	},
+	"ru": {
+		days:        [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+		shortDays:   [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+		months:      [12]string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"},
+		shortMonths: [12]string{"янв", "фев", "мар", "апр", "май", "июн", "июл", "авг", "сен", "окт", "ноя", "дек"},
+		am:          "AM",
+		pm:          "PM",
+		hours12:     false,
+	},
+	"ja": {
+		days:        [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		shortDays:   [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		months:      [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		shortMonths: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		am:          "午前",
+		pm:          "午後",
+		hours12:     false,
+	},
+	"zh": {
+		days:        [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+		shortDays:   [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+		months:      [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+		shortMonths: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		am:          "上午",
+		pm:          "下午",
+		hours12:     false,
+	},
+	"ko": {
+		days:        [7]string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"},
+		shortDays:   [7]string{"일", "월", "화", "수", "목", "금", "토"},
+		months:      [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+		shortMonths: [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+		am:          "오전",
+		pm:          "오후",
+		hours12:     true,
+	},
+}
+
+// normalizeLocale converts the locale name to its canonical lookup form (e.g.
+// "pt_BR" becomes "pt-br").
+func normalizeLocale(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", "-"))
+}
+
+// findLocale looks up the locale with the given name. If there is no exact
+// match, the base language is used (e.g. "pt" for "pt-BR"). An empty name
+// refers to the default locale.
+func findLocale(name string) (locale, bool) {
+	name = normalizeLocale(name)
+	if name == "" {
+		name = LocaleDefault
+	}
+	if l, ok := locales[name]; ok {
+		return l, true
+	}
+	if base, _, found := strings.Cut(name, "-"); found {
+		if l, ok := locales[base]; ok {
+			return l, true
+		}
+	}
+	return locale{}, false
+}
+
+// getLocale returns the locale with the given name (falling back to the
+// default locale for unknown names).
+func getLocale(name string) locale {
+	if l, ok := findLocale(name); ok {
+		return l
+	}
+	return locales[LocaleDefault]
+}
+
+// checkLocale checks if the given locale is supported (empty refers to the
+// default).
+func checkLocale(name string) error {
+	if _, ok := findLocale(name); !ok {
+		names := make([]string, 0, len(locales))
+		for n := range locales {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unsupported locale: %s (supported: %s)", name, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// meridiem returns the marker for before or after noon of the given time.
+func (l locale) meridiem(t time.Time) string {
+	if t.Hour() < 12 {
+		return l.am
+	}
+	return l.pm
+}
+
+// layoutNameTokens are the Go layout elements referring to names (longer
+// elements first, as they take precedence).
+var layoutNameTokens = []string{"January", "Jan", "Monday", "Mon", "PM", "pm"}
+
+// formatLayout formats the time using the given Go layout, but uses the names
+// of the given locale.
+func formatLayout(layout string, t time.Time, l locale) string {
+	sb := strings.Builder{}
+	start := 0
+	for i := 0; i < len(layout); i++ {
+		for _, token := range layoutNameTokens {
+			if !strings.HasPrefix(layout[i:], token) {
+				continue
+			}
+			// Like Go, only treat short names as such if not followed by a
+			// lowercase letter (e.g., "Month" is kept as it is)
+			if (token == "Jan" || token == "Mon") && startsWithLowerCase(layout[i+len(token):]) {
+				continue
+			}
+			// Format everything up to the name using the Go layout
+			sb.WriteString(t.Format(layout[start:i]))
+			// Insert localized name
+			switch token {
+			case "January":
+				sb.WriteString(l.months[t.Month()-1])
+			case "Jan":
+				sb.WriteString(l.shortMonths[t.Month()-1])
+			case "Monday":
+				sb.WriteString(l.days[t.Weekday()])
+			case "Mon":
+				sb.WriteString(l.shortDays[t.Weekday()])
+			case "PM":
+				sb.WriteString(l.meridiem(t))
+			case "pm":
+				sb.WriteString(strings.ToLower(l.meridiem(t)))
+			}
+			i += len(token)
+			start = i
+			i-- // compensate loop increment
+			break
+		}
+	}
+	sb.WriteString(t.Format(layout[start:]))
+	return sb.String()
+}
+
+// startsWithLowerCase reports whether the string starts with a lowercase ASCII
+// letter.
+func startsWithLowerCase(s string) bool {
+	return len(s) > 0 && 'a' <= s[0] && s[0] <= 'z'
+}
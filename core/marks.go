@@ -0,0 +1,134 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxMarks is the maximum number of marks (labeled A to Z).
+const maxMarks = 26
+
+// Mark is a requested time marked in the bars.
+type Mark struct {
+	// Label describes the time (optional, e.g. "standup").
+	Label string
+	// Time is the requested time.
+	Time time.Time
+}
+
+// markSymbol returns the symbol of the mark with the given index.
+func markSymbol(i int) string {
+	return string(rune('A' + i))
+}
+
+// isMarkArg indicates whether the given argument is a requested time (starting
+// with a digit or parsable, e.g. RFC 2822), optionally labeled (e.g.
+// "standup=10:00").
+func isMarkArg(config Config, arg string) bool {
+	if _, value, found := strings.Cut(arg, "="); found {
+		arg = value
+	}
+	if len(arg) > 0 && arg[0] >= '0' && arg[0] <= '9' {
+		return true
+	}
+	_, err := ParseRequestTime(config, arg)
+	return err == nil
+}
+
+// parseMarks parses the given requested times (optionally labeled via
+// <label>=<time>).
+func parseMarks(config Config, args []string) ([]Mark, error) {
+	if len(args) > maxMarks {
+		return nil, fmt.Errorf("too many times requested: %d (at most %d)", len(args), maxMarks)
+	}
+	marks := make([]Mark, len(args))
+	for i, arg := range args {
+		if label, value, found := strings.Cut(arg, "="); found {
+			marks[i].Label, arg = strings.TrimSpace(label), value
+		}
+		t, err := ParseRequestTime(config, arg)
+		if err != nil {
+			return nil, err
+		}
+		marks[i].Time = t
+	}
+	return marks, nil
+}
+
+// markSlots returns the slot of each mark within the given slots (-1, if
+// outside).
+func markSlots(marks []Mark, timeSlots []timeslot) []int {
+	slots := make([]int, len(marks))
+	for i, m := range marks {
+		slots[i] = -1
+		for j, slot := range timeSlots {
+			if !m.Time.Before(slot.Time) && m.Time.Before(slot.Time.Add(slot.Length)) {
+				slots[i] = j
+				break
+			}
+		}
+	}
+	return slots
+}
+
+// markInfos returns the time info column of each mark for the location (the
+// day offset is given relative to the reference time, if it differs).
+func markInfos(cfg Config, marks []Mark, location *time.Location, ref time.Time) []string {
+	infos := make([]string, len(marks))
+	ref = ref.In(location)
+	for i, m := range marks {
+		tzTime := m.Time.In(location)
+		infos[i] = markSymbol(i) + " " + formatHeader(cfg, tzTime) + formatDayDifference(tzTime, ref, "")
+	}
+	return infos
+}
+
+// placeMarkers writes the given symbols at the given positions of the line
+// (padding it as necessary, positions within the line are skipped).
+func placeMarkers(line string, positions []int, symbols []string) string {
+	order := make([]int, len(positions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return positions[order[a]] < positions[order[b]] })
+	for _, i := range order {
+		if positions[i] < textWidth(line) {
+			continue
+		}
+		line = padRight(line, positions[i]) + symbols[i]
+	}
+	return line
+}
+
+// plotMarkLegend plots the labels of the marks (if any are labeled).
+func plotMarkLegend(plt Plotter, marks []Mark) {
+	var labels []string
+	for i, m := range marks {
+		if m.Label != "" {
+			labels = append(labels, markSymbol(i)+" = "+m.Label)
+		}
+	}
+	if len(labels) > 0 {
+		plt.PlotLine(ContextNormal, "marks: "+strings.Join(labels, ", "))
+	}
+}
+
+// plotMarkTimes plots the time of each mark in every location (the vertical
+// layout has no info columns, the day offset is given relative to the
+// reference time, if it differs).
+func plotMarkTimes(plt Plotter, cfg Config, marks []Mark, locations []locationContainer, ref time.Time) {
+	for i, m := range marks {
+		times := make([]string, len(locations))
+		for j, location := range locations {
+			tzTime := m.Time.In(location.location)
+			times[j] = abbreviate(location) + " " + formatHeader(cfg, tzTime) + formatDayDifference(tzTime, ref.In(location.location), "")
+		}
+		head := markSymbol(i)
+		if m.Label != "" {
+			head += " " + m.Label
+		}
+		plt.PlotLine(ContextNormal, truncate(head+": "+strings.Join(times, " · "), plt.TerminalWidth))
+	}
+}
@@ -1,16 +1,21 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
 )
 
 type timeslot struct {
+	// Start of the slot
 	Time time.Time
+	// Length of the slot
+	Length time.Duration
 }
 
 // Plotter compiles functionality & configuration for plotting.
@@ -19,7 +24,7 @@ type Plotter struct {
 	PlotLine func(t ContextType, msgs ...interface{})
 	// func for plotting simple strings
 	PlotString func(t ContextType, msg string)
-	// All symbols to represent the hours
+	// All symbols to represent the minutes of the day
 	Symbols []string
 	// Terminal width
 	TerminalWidth int
@@ -28,58 +33,89 @@ type Plotter struct {
 }
 
 // formatTime formats the time in the default way (distinguishing 12/24 hours
-// though).
-func formatTime(twelve, flush bool, t time.Time) string {
+// and the clock precision though).
+func formatTime(l locale, twelve, flush bool, precision string, t time.Time) string {
+	seconds := precision == PrecisionSeconds
 	if twelve {
 		// 12-hour format
-		s := t.Format("3:04PM")
+		layout := "3:04PM"
+		if seconds {
+			layout = "3:04:05PM"
+		}
+		s := formatLayout(layout, t, l)
 		if flush {
-			return fmt.Sprintf("%7s", s)
+			// Align with the longest times possible (before and after noon)
+			am := formatLayout(layout, time.Date(0, 1, 1, 11, 59, 59, 0, time.UTC), l)
+			pm := formatLayout(layout, time.Date(0, 1, 1, 23, 59, 59, 0, time.UTC), l)
+			return padLeft(s, max(textWidth(am), textWidth(pm)))
 		}
 		return s
 	} else {
 		// 24-hour format
+		if seconds {
+			return t.Format("15:04:05")
+		}
 		return t.Format("15:04")
 	}
 }
 
-// updateTimeNeeded indicates whether the time shown should be updated.
-func updateTimeNeeded(shown, now time.Time) bool {
-	// Check if time has changed
-	return shown.Second() != now.Second() ||
-		shown.Minute() != now.Minute() ||
-		shown.Hour() != now.Hour() ||
-		shown.Day() != now.Day() ||
-		shown.Month() != now.Month() ||
-		shown.Year() != now.Year()
+// updateTimeNeeded indicates whether the time shown should be updated, i.e.,
+// whether the given times fall into different refresh intervals.
+func updateTimeNeeded(shown, now time.Time, interval time.Duration) bool {
+	return !shown.Truncate(interval).Equal(now.Truncate(interval))
+}
+
+// nextRefresh returns the start of the refresh interval following the given
+// time.
+func nextRefresh(t time.Time, interval time.Duration) time.Time {
+	return t.Truncate(interval).Add(interval)
 }
 
 // formatDay formats the day in the default way.
-func formatDay(twelve bool, t time.Time) string {
-	return t.Format("Mon 02 Jan 2006")
+func formatDay(l locale, t time.Time) string {
+	return formatLayout("Mon 02 Jan 2006", t, l)
 }
 
-// getHourSymbol returns a symbol representing the hour in a day.
-func getHourSymbol(plotter Plotter, hour int) string {
+// getSymbol returns a symbol representing the time of the day.
+func getSymbol(plotter Plotter, t DayTime) string {
 	// Small sanity check
-	if hour < 0 || hour > 23 {
-		panic(fmt.Sprintf("invalid hour: %d", hour))
+	if t < 0 || t >= MinutesPerDay {
+		panic(fmt.Sprintf("invalid time of day: %d", t))
 	}
-	// Returns symbol representing the hour
-	return plotter.Symbols[hour]
+	// Returns symbol representing the time of the day
+	return plotter.Symbols[t]
 }
 
 // Plot is the main plotting function. It either plots to the terminal in a
 // conventional way or uses tcell for providing a continuously updating plot.
 func Plot(c Config, t time.Time) error {
-	if c.Live && t.IsZero() /* Only enter live mode if no time was requested */ {
-		// --> Plot time using tcell
-		// Initialize styles
-		styles := map[ContextType]tcell.Style{}
-		if c.Style.Colorize {
-			styles = getDynamicColorMap(c.Style.Coloring)
+	// Load the team once, if desired
+	c, err := resolveTeamView(c)
+	if err != nil {
+		return err
+	}
+	if c.Export.Format != "" {
+		// --> Export the time (the current one, if none was requested)
+		now := time.Now()
+		if t.IsZero() {
+			t = now.Truncate(time.Minute)
 		}
-
+		return WriteExport(os.Stdout, c, t, now)
+	}
+	if c.StatusBar != "" {
+		// --> Stream updates for a status bar (or write a single one, if a
+		// specific time was requested)
+		if !t.IsZero() {
+			update, err := createStatusUpdate(c, t)
+			if err != nil {
+				return err
+			}
+			return writeStatusUpdate(os.Stdout, c.StatusBar, update, true)
+		}
+		return StreamStatusBar(context.Background(), os.Stdout, c, time.Now)
+	}
+	if c.Live && t.IsZero() /* Only enter live mode if no time was requested */ && c.Output != OutputLine {
+		// --> Plot time using tcell
 		// Initialize screen
 		s, err := tcell.NewScreen()
 		if err != nil {
@@ -88,158 +124,242 @@ func Plot(c Config, t time.Time) error {
 		if err := s.Init(); err != nil {
 			return fmt.Errorf("error initializing tcell screen for live view - %+v", err)
 		}
-		exit := func() {
-			s.Fini()
-			os.Exit(0)
-		}
+		defer s.Fini()
+		return PlotLive(context.Background(), s, c, time.Now)
+	}
 
-		// Track update events
-		width, height := s.Size()
-		now := time.Time{} // Requested time is discarded in live mode; first 'now' is set to trigger refresh
+	// --> Plot time using fmt
+	// Prepare plotter
+	plt := newStaticPlotter(c, t.IsZero())
+	// Get current time, if no specific time was requested
+	if plt.Now {
+		t = time.Now()
+	}
+	// Plot
+	return PlotTime(plt, c, t)
+}
 
-		// Define plotting functions for tcell
-		x, y := 0, 0
-		plotLine := func(t ContextType, msgs ...interface{}) {
-			// Get style
-			style := tcell.StyleDefault
-			if c.Style.Colorize {
-				style = styles[t]
-			}
-			// Print message
-			for _, msg := range msgs {
-				for _, r := range fmt.Sprint(msg) {
-					s.SetContent(x, y, r, nil, style)
-					x++
-				}
-			}
-			// Fill previous line to the end
-			for i := x; i < width; i++ {
-				s.SetContent(i, y, ' ', nil, style)
+// newStaticPlotter creates a plotter printing to the terminal via fmt.
+func newStaticPlotter(c Config, now bool) Plotter {
+	colorMap := getStaticColorMap(c.Style.Coloring)
+	colorize := colorizeOutput(c)
+	return Plotter{
+		Now:           now,
+		TerminalWidth: getTerminalWidth(),
+		PlotLine: func(t ContextType, line ...interface{}) {
+			if ch, ok := colorMap[t]; ok && ch != "" && colorize {
+				fmt.Println(ch + fmt.Sprint(line...) + ColorReset)
+			} else {
+				fmt.Println(line...)
 			}
-			// Move cursor to next line
-			x = 0
-			y++
-		}
-		plotString := func(t ContextType, msg string) {
-			// Get style
-			style := tcell.StyleDefault
-			if c.Style.Colorize {
-				style = styles[t]
+		},
+		PlotString: func(t ContextType, msg string) {
+			if ch, ok := colorMap[t]; ok && ch != "" && colorize {
+				fmt.Print(ch + fmt.Sprint(msg) + ColorReset)
+			} else {
+				fmt.Print(msg)
 			}
-			// Print message
+		},
+		Symbols: GetSymbols(c.Style),
+	}
+}
+
+// PlotLive continuously plots the time to the given (already initialized)
+// screen. It returns when the user quits (via 'q', 'esc' or 'ctrl+c') or the
+// context is cancelled. The screen is not finalized, this is left to the
+// caller. The clock function is used to determine the current time.
+func PlotLive(ctx context.Context, s tcell.Screen, c Config, clock func() time.Time) error {
+	// Load the team once (instead of on every redraw), if desired
+	c, err := resolveTeamView(c)
+	if err != nil {
+		return err
+	}
+
+	// Initialize styles
+	styles := map[ContextType]tcell.Style{}
+	if c.Style.Colorize {
+		styles = getDynamicColorMap(c.Style.Coloring)
+	}
+
+	// Track screen size
+	width, height := s.Size()
+
+	// Define plotting functions for tcell
+	x, y := 0, 0
+	plotLine := func(t ContextType, msgs ...interface{}) {
+		// Get style
+		style := tcell.StyleDefault
+		if c.Style.Colorize {
+			style = styles[t]
+		}
+		// Print message
+		for _, msg := range msgs {
 			for _, r := range fmt.Sprint(msg) {
 				s.SetContent(x, y, r, nil, style)
-				x++
+				x += runewidth.RuneWidth(r)
 			}
 		}
+		// Fill previous line to the end
+		for i := x; i < width; i++ {
+			s.SetContent(i, y, ' ', nil, style)
+		}
+		// Move cursor to next line
+		x = 0
+		y++
+	}
+	plotString := func(t ContextType, msg string) {
+		// Get style
+		style := tcell.StyleDefault
+		if c.Style.Colorize {
+			style = styles[t]
+		}
+		// Print message
+		for _, r := range fmt.Sprint(msg) {
+			s.SetContent(x, y, r, nil, style)
+			x += runewidth.RuneWidth(r)
+		}
+	}
+
+	// Prepare plotter
+	plt := Plotter{
+		PlotLine:   plotLine,
+		PlotString: plotString,
+		Symbols:    GetSymbols(c.Style),
+		Now:        true,
+	}
 
-		// Prepare plotter
-		plt := Plotter{
-			PlotLine:   plotLine,
-			PlotString: plotString,
-			Symbols:    GetSymbols(c.Style),
-			Now:        true,
+	// Define drawing of a full frame (only changed cells are sent to the
+	// terminal by Show)
+	shown := time.Time{}
+	draw := func() error {
+		shown = clock()
+		x, y = 0, 0
+		plt.TerminalWidth = width
+		err := PlotTime(plt, c, shown)
+		if err != nil {
+			return err
 		}
+		// Fill remaining lines
+		for i := y; i < height; i++ {
+			for j := 0; j < width; j++ {
+				s.SetContent(j, i, ' ', nil, styles[ContextNormal])
+			}
+		}
+		// Update screen
+		s.Show()
+		return nil
+	}
 
-		// Refresh time periodically
-		updateTimeout := time.Duration(40) * time.Millisecond
+	// Refresh at the precision of the clock shown
+	interval := refreshInterval(c.Precision)
 
-		// Enter main loop
+	// Wake up the event loop whenever the time shown needs to be refreshed or
+	// the context is cancelled
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
 		for {
-			// Check whether to refresh the plot (due to time or resizing)
-			w, h := s.Size()
-			t = time.Now()
-			if w != width || h != height || updateTimeNeeded(now, t) {
-				// Update dynamic plot information
-				width, height = w, h
-				now = t
-				x, y = 0, 0
-				plt.TerminalWidth = w
-				// Refresh time (pass zero time to indicate now should be used)
-				s.Clear()
-				err := PlotTime(plt, c, now)
-				if err != nil {
-					return err
-				}
-				// Fill remaining lines
-				for i := y; i < h; i++ {
-					for j := 0; j < w; j++ {
-						s.SetContent(j, i, ' ', nil, styles[ContextNormal])
-					}
-				}
-				// Update screen
-				s.Sync()
+			now := clock()
+			timer := time.NewTimer(nextRefresh(now, interval).Sub(now))
+			select {
+			case <-stop:
+				timer.Stop()
+				return
+			case <-ctx.Done():
+				timer.Stop()
+				_ = s.PostEvent(tcell.NewEventInterrupt(nil))
+				return
+			case <-timer.C:
+				// A full event queue already wakes up the loop, ignore errors
+				_ = s.PostEvent(tcell.NewEventInterrupt(nil))
 			}
+		}
+	}()
 
-			// Poll event or simply wait
-			if s.HasPendingEvent() {
-				// Grab the event
-				ev := s.PollEvent()
-				// Process event
-				switch ev := ev.(type) {
-				case *tcell.EventResize:
-					s.Sync()
-				case *tcell.EventKey:
-					if ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q' {
-						exit()
-					}
-				}
-			} else {
-				// Just sleep before redrawing
-				time.Sleep(updateTimeout)
-			}
+	// Draw initial frame
+	if err := draw(); err != nil {
+		return err
+	}
+
+	// Enter main loop (blocks until the next event arrives)
+	for {
+		ev := s.PollEvent()
+		// Stop, if requested
+		if ctx.Err() != nil {
+			return nil
 		}
-	} else {
-		// --> Plot time using fmt
-		// Prepare plotter
-		colorMap := getStaticColorMap(c.Style.Coloring)
-		plt := Plotter{
-			Now:           t.IsZero(),
-			TerminalWidth: getTerminalWidth(),
-			PlotLine: func(t ContextType, line ...interface{}) {
-				if ch, ok := colorMap[t]; ok && ch != "" && c.Style.Colorize {
-					fmt.Println(ch + fmt.Sprint(line) + ColorReset)
-				} else {
-					fmt.Println(line...)
-				}
-			},
-			PlotString: func(t ContextType, msg string) {
-				if ch, ok := colorMap[t]; ok && ch != "" && c.Style.Colorize {
-					fmt.Print(ch + fmt.Sprint(msg) + ColorReset)
-				} else {
-					fmt.Print(msg)
+		// Process event
+		switch ev := ev.(type) {
+		case nil:
+			// Screen was finalized
+			return nil
+		case *tcell.EventResize:
+			width, height = ev.Size()
+			if err := draw(); err != nil {
+				return err
+			}
+		case *tcell.EventInterrupt:
+			if updateTimeNeeded(shown, clock(), interval) {
+				if err := draw(); err != nil {
+					return err
 				}
-			},
-			Symbols: GetSymbols(c.Style),
-		}
-		// Get current time, if no specific time was requested
-		if plt.Now {
-			t = time.Now()
-		}
-		// Plot
-		err := PlotTime(plt, c, t)
-		if err != nil {
-			return err
+			}
+		case *tcell.EventKey:
+			if ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q' {
+				return nil
+			}
 		}
 	}
-
-	return nil
 }
 
 // PlotTime plots the time on the terminal.
 func PlotTime(plt Plotter, cfg Config, t time.Time) error {
+	// Show the zones of the team instead, if desired (and not resolved yet)
+	cfg, err := resolveTeamView(cfg)
+	if err != nil {
+		return err
+	}
+	// Print a single line instead, if desired
+	if cfg.Output == OutputLine {
+		return plotLine(plt, cfg, t)
+	}
+	// Plot the week grid instead, if desired
+	if cfg.View == ViewWeek {
+		return plotWeek(plt, cfg, t)
+	}
+	// Plot columns instead of bars, if desired
+	if cfg.Layout == LayoutVertical {
+		return plotVertical(plt, cfg, t)
+	}
+
 	// Get infos and time zones for all locations
-	timeInfos, timeZones, err := createTimeInfos(cfg, t)
+	timeInfos, locations, err := createTimeInfos(cfg, t)
 	if err != nil {
 		return err
 	}
+	// Add a time info column per mark (aligned by display width)
+	if len(cfg.Marks) > 0 {
+		infos := make([][]string, len(locations))
+		widths := make([]int, len(cfg.Marks))
+		for i, location := range locations {
+			infos[i] = markInfos(cfg, cfg.Marks, location.location, t)
+			for j, info := range infos[i] {
+				widths[j] = max(widths[j], textWidth(info))
+			}
+		}
+		for i := range timeInfos {
+			for j, info := range infos[i] {
+				timeInfos[i] += " " + padRight(info, widths[j])
+			}
+		}
+	}
 
 	// Determine time info width
 	timeInfoWidth := 0
 	if cfg.Inline {
 		for _, ti := range timeInfos {
-			if len(ti) > timeInfoWidth {
-				timeInfoWidth = len(ti)
+			if w := textWidth(ti); w > timeInfoWidth {
+				timeInfoWidth = w
 			}
 		}
 		timeInfoWidth++ // Leave a space between time info and bars
@@ -250,43 +370,118 @@ func PlotTime(plt Plotter, cfg Config, t time.Time) error {
 	if width < 0 {
 		width = 0
 	}
-	// Set hours to plot
-	hours := 24
+	// Set time span to plot
+	span := 24 * time.Hour
 	// Use integral time slots with no rounding issues, if desired
 	if !cfg.Stretch {
 		width = width / 24 * 24
 	}
-	// Determine time slot basics
+	// Determine time slot basics (the slots cover the full span with the
+	// requested time at the start of the now slot)
 	timeSlots := make([]timeslot, width)
 	nowSlot := width / 2
-	slotMinutes := 0
+	var slotLength time.Duration
 	if width > 0 {
-		slotMinutes = hours * 60 / width
+		slotLength = span / time.Duration(width)
 	}
-	offsetMinutes := slotMinutes * width / 2
-	// Plot header
-	nowTag := "now"
-	if !plt.Now {
-		nowTag = "time"
-	}
-	headLine := strings.Repeat(" ",
-		timeInfoWidth+nowSlot-(len(nowTag)+1)) +
-		nowTag + " v " +
-		formatTime(cfg.Hours12, false, t)
-	if len(headLine) > plt.TerminalWidth {
-		// Truncate head line if it is too long
-		headLine = headLine[:plt.TerminalWidth]
-	}
-	plt.PlotLine(ContextNormal, headLine)
+	start := t.Add(-span / 2)
 	// Prepare slots
 	for i := 0; i < width; i++ {
-		// Get time of slot
-		slotTime := t.Add(time.Duration(i*slotMinutes-offsetMinutes) * time.Minute)
+		// Get time of slot (computed from the span to avoid rounding drift)
+		slotTime := start.Add(span * time.Duration(i) / time.Duration(width))
 		// Store timeslot info
 		timeSlots[i] = timeslot{
-			Time: slotTime,
+			Time:   slotTime,
+			Length: slotLength,
+		}
+	}
+	// Determine the markers (the requested times or now)
+	markerSlots, markerSymbols := []int{nowSlot}, []string{"|"}
+	if len(cfg.Marks) > 0 {
+		markerSlots, markerSymbols = nil, nil
+		for i, slot := range markSlots(cfg.Marks, timeSlots) {
+			if slot >= 0 {
+				markerSlots = append(markerSlots, slot)
+				markerSymbols = append(markerSymbols, markSymbol(i))
+			}
+		}
+	}
+	var rangeSlots []int
+	var rangeSymbols []string
+	if !cfg.Range.IsZero() {
+		// Enclose the requested range in the bars (instead of marking its
+		// start), the brackets keep it visible without colors too
+		if len(cfg.Marks) == 0 {
+			markerSlots, markerSymbols = nil, nil
+		}
+		first, last := -1, -1
+		for j, slot := range timeSlots {
+			if cfg.Range.overlaps(slot) {
+				if first < 0 {
+					first = j
+				}
+				last = j
+			}
+		}
+		if first > 0 {
+			rangeSlots = append(rangeSlots, first-1)
+			rangeSymbols = append(rangeSymbols, RangeStartSymbol)
+		}
+		if last >= 0 && last < width-1 {
+			rangeSlots = append(rangeSlots, last+1)
+			rangeSymbols = append(rangeSymbols, RangeEndSymbol)
 		}
 	}
+	// Plot header
+	var headLine string
+	if len(cfg.Marks) > 0 {
+		// Label the marks
+		positions := make([]int, len(markerSlots))
+		for i, slot := range markerSlots {
+			positions[i] = timeInfoWidth + slot
+		}
+		headLine = placeMarkers("", positions, markerSymbols)
+	} else {
+		nowTag := "now"
+		if !plt.Now {
+			nowTag = "time"
+		}
+		indent := timeInfoWidth + nowSlot - (len(nowTag) + 1)
+		zone := ""
+		if !plt.Now {
+			// Show the zone the requested time was interpreted in (before the
+			// tag, if there is room)
+			zone = describeZone(cfg, t)
+			if w := textWidth(zone) + 1; w <= indent {
+				nowTag = zone + " " + nowTag
+				indent -= w
+				zone = ""
+			}
+		}
+		headLine = strings.Repeat(" ", indent) +
+			nowTag + " v " +
+			formatHeader(cfg, t)
+		if !cfg.Range.IsZero() {
+			headLine += "-" + formatRangeEnd(cfg, cfg.Range, t.Location())
+		}
+		if zone != "" {
+			headLine += " " + zone
+		}
+	}
+	if textWidth(headLine) > plt.TerminalWidth {
+		// Truncate head line if it is too long
+		headLine = truncate(headLine, plt.TerminalWidth)
+	}
+	plt.PlotLine(ContextNormal, headLine)
+	// Get calendar events within the span
+	events, err := loadEvents(cfg.Calendars, start, start.Add(span))
+	if err != nil {
+		return err
+	}
+	eventSymbol := cfg.Style.EventSymbol
+	if eventSymbol == "" {
+		eventSymbol = EventSymbolDefault
+	}
 
 	// Plot all locations
 	for i := range timeInfos {
@@ -297,60 +492,160 @@ func PlotTime(plt Plotter, cfg Config, t time.Time) error {
 			timeInfo += " "
 			plt.PlotString(ContextNormal, timeInfo)
 		} else {
-			// Plot time info (also add the vertical marker) and start new line
-			if len(timeInfo)-1 < nowSlot {
-				timeInfo = timeInfo + strings.Repeat(" ", nowSlot-len(timeInfo)) + "|"
-			}
-			plt.PlotLine(ContextNormal, timeInfo)
+			// Plot time info (also add the vertical markers) and start new line
+			plt.PlotLine(ContextNormal, placeMarkers(timeInfo, markerSlots, markerSymbols))
 		}
 		// --> Plot timeslots
+		symbols := make([]string, width)
+		segments := make([]ContextType, width)
 		for j := 0; j < width; j++ {
-			// Convert to tz time
-			tzTime := timeSlots[j].Time.In(timeZones[i])
-			// Get symbol of slot
-			s := getHourSymbol(plt, tzTime.Hour())
-			// Get segment type of slot
-			seg := getDaySegment(cfg.Style.DaySegmentation, tzTime.Hour())
-			if j == nowSlot {
-				s = "|"
-				seg = ContextNormal
+			symbols[j], segments[j] = getSlotStyle(plt, cfg, locations[i], timeSlots[j].Time)
+			// Mark events
+			if eventAt(events, timeSlots[j]) {
+				symbols[j], segments[j] = eventSymbol, ContextEvent
 			}
-			plt.PlotString(seg, s)
+		}
+		// Mark day changes
+		if cfg.Style.Midnight == MidnightModeMarker || cfg.Style.Midnight == MidnightModeLabel {
+			markMidnight(cfg, timeSlots, locations[i].location, nowSlot, symbols, segments)
+		}
+		// Highlight the requested range
+		for j := 0; j < width; j++ {
+			if cfg.Range.overlaps(timeSlots[j]) {
+				segments[j] = rangeContext(segments[j])
+			}
+		}
+		// Mark now (or the requested times) and enclose the requested range
+		for j, slot := range rangeSlots {
+			symbols[slot] = rangeSymbols[j]
+			segments[slot] = ContextNormal
+		}
+		for j, slot := range markerSlots {
+			if slot < width {
+				symbols[slot] = markerSymbols[j]
+				segments[slot] = ContextNormal
+			}
+		}
+		for j := 0; j < width; j++ {
+			plt.PlotString(segments[j], symbols[j])
 		}
 		plt.PlotLine(ContextNormal)
 	}
 
 	// Plot tics
 	if cfg.Tics {
-		plotTics(plt, cfg.Hours12, timeSlots, width)
+		plotTics(plt, cfg, timeSlots, width)
 	}
 
+	// Plot labels of the marks
+	plotMarkLegend(plt, cfg.Marks)
+
+	// Plot legend of upcoming events
+	plotEvents(plt, cfg, events, locations, t)
+
 	return nil
 }
 
+// getSlotStyle returns the symbol and the segment of the slot starting at the
+// given time for the given location.
+func getSlotStyle(plt Plotter, cfg Config, location locationContainer, t time.Time) (string, ContextType) {
+	// Convert to tz time
+	tzTime := t.In(location.location)
+	// Get symbol of slot
+	symbol := getSymbol(plt, dayTimeOf(tzTime))
+	// Get segment type of slot
+	segment := getLocationSegment(cfg, location, tzTime)
+	if cfg.Style.DaySegmentation.Mode == SegmentationModeSun && location.hasCoordinates {
+		// Symbols follow the actual position of the sun, if they depict it
+		if sym, ok := segmentSymbols(cfg.Style.Symbols)[segment]; ok {
+			symbol = sym
+		}
+	}
+	// Color by light level, if desired
+	if cfg.Style.ColorMode == ColorModeGradient {
+		segment = gradientContext(getLightLevel(tzTime, location.coordinates, location.hasCoordinates))
+	}
+	return symbol, segment
+}
+
+// getLocationSegment returns the day segment of the location at the given time
+// (in its timezone).
+func getLocationSegment(cfg Config, location locationContainer, tzTime time.Time) ContextType {
+	if cfg.Style.DaySegmentation.Mode == SegmentationModeSun && location.hasCoordinates {
+		// Use the actual position of the sun
+		return getSunSegment(tzTime, location.coordinates)
+	}
+	return getDaySegment(cfg.Style.DaySegmentation, dayTimeOf(tzTime))
+}
+
+// markMidnight marks the slots at which the day changes in the given timezone
+// (and labels them with the new weekday, if desired).
+func markMidnight(cfg Config, timeSlots []timeslot, tz *time.Location, nowSlot int, symbols []string, segments []ContextType) {
+	symbol := cfg.Style.MidnightSymbol
+	if symbol == "" {
+		symbol = MidnightSymbolDefault
+	}
+	l := getLocale(cfg.Locale)
+	for j := 1; j < len(timeSlots); j++ {
+		// Check for day change
+		prev, curr := timeSlots[j-1].Time.In(tz), timeSlots[j].Time.In(tz)
+		if prev.Day() == curr.Day() {
+			continue
+		}
+		symbols[j], segments[j] = symbol, ContextMidnight
+		if cfg.Style.Midnight != MidnightModeLabel {
+			continue
+		}
+		// Label with the new weekday, if there is enough space (without
+		// covering the now marker)
+		label := l.shortDays[curr.Weekday()]
+		w := textWidth(label)
+		if j+w >= len(symbols) || (nowSlot > j && nowSlot <= j+w) {
+			continue
+		}
+		k := j + 1
+		for _, r := range label {
+			symbols[k], segments[k] = string(r), ContextMidnight
+			// Wide characters occupy the following slot too
+			rw := runewidth.RuneWidth(r)
+			for x := 1; x < rw; x++ {
+				symbols[k+x] = ""
+			}
+			k += rw
+		}
+	}
+}
+
 // createTimeInfos creates the time info strings for all locations.
-func createTimeInfos(cfg Config, t time.Time) (timeInfos []string, timeZones []*time.Location, err error) {
+func createTimeInfos(cfg Config, t time.Time) (timeInfos []string, locations []locationContainer, err error) {
 	// Prepare timezones for plotting
-	locations := make([]locationContainer, len(cfg.Timezones)+1)
+	locations = make([]locationContainer, len(cfg.Timezones)+1)
 	now := time.Now()
 	_, localOffset := now.In(time.Local).Zone()
+	localCoords, localHasCoords := localCoordinates()
 	locations[0] = locationContainer{
-		location:    time.Local,
-		description: "Local",
-		offset:      localOffset,
+		location:       time.Local,
+		description:    "Local",
+		offset:         localOffset,
+		coordinates:    localCoords,
+		hasCoordinates: localHasCoords,
 	}
 	for i, tz := range cfg.Timezones {
 		// Get timezone
-		loc, err := time.LoadLocation(tz.TZ)
+		loc, err := loadLocation(tz.TZ)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error loading timezone %s: %s", tz.TZ, err)
 		}
 		_, offset := now.In(loc).Zone()
+		coords, hasCoords := resolveCoordinates(tz)
 		// Store timezone
 		locations[i+1] = locationContainer{
-			location:    loc,
-			description: tz.Name,
-			offset:      offset,
+			location:       loc,
+			description:    tz.Name,
+			abbreviation:   tz.Abbreviation,
+			offset:         offset,
+			coordinates:    coords,
+			hasCoordinates: hasCoords,
 		}
 	}
 
@@ -359,48 +654,56 @@ func createTimeInfos(cfg Config, t time.Time) (timeInfos []string, timeZones []*
 		sortLocations(locations, cfg.Sorting, cfg.SortLocalTop)
 	}
 
-	// Determine max description length
-	descriptionLength := 0
-	for _, location := range locations {
-		if len(location.description) > descriptionLength {
-			descriptionLength = len(location.description)
+	// Determine columns to show
+	columns := cfg.Columns
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+
+	// Get column values and determine max column widths
+	values := make([][]string, len(locations))
+	widths := make([]int, len(columns))
+	for i, location := range locations {
+		values[i] = make([]string, len(columns))
+		for j, column := range columns {
+			values[i][j] = columnValue(cfg, column, location, t)
+			if w := textWidth(values[i][j]); w > widths[j] {
+				widths[j] = w
+			}
 		}
 	}
 
 	timeInfos = make([]string, len(cfg.Timezones)+1)
-	timeZones = make([]*time.Location, len(cfg.Timezones)+1)
-	for i, location := range locations {
-		// Prepare location and time infos
-		timeInfo := fmt.Sprintf("%-*s", descriptionLength, location.description)
-		timeInfo = fmt.Sprintf(
-			"%s: %s %s",
-			timeInfo,
-			formatDay(cfg.Hours12, t.In(location.location)),
-			formatTime(cfg.Hours12, true, t.In(location.location)),
-		)
-		// Store time info and timezone
-		timeInfos[i] = timeInfo
-		timeZones[i] = location.location
-	}
-
-	return timeInfos, timeZones, nil
+	for i := range locations {
+		// Prepare location and time infos (aligned by display width)
+		parts := make([]string, len(columns))
+		for j, column := range columns {
+			parts[j] = padRight(values[i][j], widths[j])
+			if column == ColumnName {
+				parts[j] += ":"
+			}
+		}
+		// Store time info
+		timeInfos[i] = strings.Join(parts, " ")
+	}
+
+	return timeInfos, locations, nil
 }
 
 // plotTics adds tics to the plot.
-func plotTics(plt Plotter, hours12 bool, timeSlots []timeslot, width int) {
+func plotTics(plt Plotter, cfg Config, timeSlots []timeslot, width int) {
 	// Prepare tics
 	tics := make([]string, width)
-	currentHour := -1
 	for i := 0; i < width; i++ {
-		// Get hour of slot
-		hour := timeSlots[i].Time.Truncate(time.Hour)
-		if hour.Hour()%3 == 0 && hour.Hour() != currentHour {
-			if hours12 {
-				tics[i] = hour.Format("3PM")
-			} else {
-				tics[i] = fmt.Sprintf("%d", hour.Hour())
-			}
-			currentHour = hour.Hour()
+		// Get the first full hour at or after the start of the slot
+		slot := timeSlots[i].Time
+		hour := time.Date(slot.Year(), slot.Month(), slot.Day(), slot.Hour(), 0, 0, 0, slot.Location())
+		if hour.Before(slot) {
+			hour = hour.Add(time.Hour)
+		}
+		// Place a tic, if the hour falls into the slot
+		if hour.Hour()%3 == 0 && hour.Before(slot.Add(timeSlots[i].Length)) {
+			tics[i] = formatTic(cfg, hour)
 		}
 	}
 	// Plot tics
@@ -414,9 +717,9 @@ func plotTics(plt Plotter, hours12 bool, timeSlots []timeslot, width int) {
 	plt.PlotLine(ContextNormal)
 	// Plot tics
 	for i := 0; i < width; i++ {
-		if tics[i] != "" && i+len(tics[i]) < width {
+		if w := textWidth(tics[i]); tics[i] != "" && i+w < width {
 			plt.PlotString(ContextNormal, tics[i])
-			i += len(tics[i]) - 1
+			i += w - 1
 		} else {
 			plt.PlotString(ContextNormal, " ")
 		}
@@ -0,0 +1,374 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/tidwall/jsonc"
+)
+
+// Define roster defaults
+var (
+	// RosterWorkStartDefault is the default start of the working hours.
+	RosterWorkStartDefault = NewDayTime(9, 0)
+	// RosterWorkEndDefault is the default end of the working hours.
+	RosterWorkEndDefault = NewDayTime(17, 0)
+	// RosterWeekendDefault are the default weekend days.
+	RosterWeekendDefault = []string{"sat", "sun"}
+)
+
+// rosterGroupLimit is the number of people named per zone in the team view
+// (further ones are counted only).
+const rosterGroupLimit = 3
+
+// Roster describes the people of a team.
+type Roster struct {
+	// People of the team.
+	People []Person `json:"people"`
+}
+
+// Person describes a member of the team.
+type Person struct {
+	// Name of the person.
+	Name string `json:"name"`
+	// Machine-readable timezone name.
+	TZ string `json:"tz"`
+	// Start of the working hours (optional, 9:00 by default).
+	WorkStart *DayTime `json:"work_start,omitempty"`
+	// End of the working hours (optional, 17:00 by default).
+	WorkEnd *DayTime `json:"work_end,omitempty"`
+	// Weekend days (optional, e.g. ["fri", "sat"], Saturday and Sunday by
+	// default).
+	Weekend []string `json:"weekend,omitempty"`
+	// Tags of the groups the person belongs to (optional).
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Define the status of people
+const (
+	// StatusWorking marks people within their working hours.
+	StatusWorking = "working"
+	// StatusAvailable marks people awake but off work.
+	StatusAvailable = "available"
+	// StatusAsleep marks people at night.
+	StatusAsleep = "asleep"
+)
+
+// workingHours returns the working hours of the person.
+func (p Person) workingHours() (start, end DayTime) {
+	start, end = RosterWorkStartDefault, RosterWorkEndDefault
+	if p.WorkStart != nil {
+		start = *p.WorkStart
+	}
+	if p.WorkEnd != nil {
+		end = *p.WorkEnd
+	}
+	return start, end
+}
+
+// weekend returns the weekend days of the person.
+func (p Person) weekend() []time.Weekday {
+	names := p.Weekend
+	if names == nil {
+		names = RosterWeekendDefault
+	}
+	days := make([]time.Weekday, 0, len(names))
+	for _, name := range names {
+		if day, ok := parseWeekday(name); ok {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// hasTag indicates whether the person has any of the given tags (or whether no
+// tags are given).
+func (p Person) hasTag(tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		for _, own := range p.Tags {
+			if strings.EqualFold(tag, own) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// status returns the status of the person at the given time.
+func (p Person) status(cfg Config, t time.Time) (string, error) {
+	loc, err := loadLocation(p.TZ)
+	if err != nil {
+		return "", fmt.Errorf("error loading timezone %s of %s: %s", p.TZ, p.Name, err)
+	}
+	tzTime := t.In(loc)
+	dayTime := dayTimeOf(tzTime)
+	// Check working hours (which may span midnight) on work days
+	start, end := p.workingHours()
+	working := dayTime >= start && dayTime < end
+	if end < start {
+		working = dayTime >= start || dayTime < end
+	}
+	workday := true
+	for _, day := range p.weekend() {
+		if tzTime.Weekday() == day {
+			workday = false
+		}
+	}
+	switch {
+	case working && workday:
+		return StatusWorking, nil
+	case getDaySegment(cfg.Style.DaySegmentation, dayTime) == ContextNight:
+		return StatusAsleep, nil
+	default:
+		return StatusAvailable, nil
+	}
+}
+
+// parseWeekday parses a weekday given by its English name or abbreviation
+// (e.g. "sat" or "Saturday").
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) < 2 {
+		return 0, false
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.HasPrefix(strings.ToLower(day.String()), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// validate validates the roster.
+func (r Roster) validate() error {
+	for _, p := range r.People {
+		if p.Name == "" {
+			return fmt.Errorf("person without name (timezone %s)", p.TZ)
+		}
+		if !checkTimezoneLocation(p.TZ) {
+			return fmt.Errorf("invalid timezone of %s: %s", p.Name, p.TZ)
+		}
+		for _, name := range p.Weekend {
+			if _, ok := parseWeekday(name); !ok {
+				return fmt.Errorf("invalid weekend day of %s: %s", p.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// defaultRosterFiles are the paths searched for a roster, if none is
+// configured.
+func defaultRosterFiles() []string {
+	return []string{
+		filepath.Join(xdg.ConfigHome, "gotz", "roster.json"),
+		filepath.Join(xdg.ConfigHome, "gotz", "roster.csv"),
+	}
+}
+
+// LoadRoster loads the configured roster (or the default one in the config
+// directory). Rosters are given as JSON or CSV (by file extension).
+func LoadRoster(cfg Config) (Roster, error) {
+	files := defaultRosterFiles()
+	if cfg.Roster != "" {
+		files = []string{cfg.Roster}
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) && cfg.Roster == "" {
+			continue
+		}
+		if err != nil {
+			return Roster{}, errors.New("Error reading roster: " + err.Error())
+		}
+		var roster Roster
+		if strings.EqualFold(filepath.Ext(file), ".csv") {
+			roster, err = parseRosterCSV(strings.NewReader(string(data)))
+		} else {
+			err = json.Unmarshal(jsonc.ToJSON(data), &roster)
+		}
+		if err != nil {
+			return Roster{}, errors.New("Error parsing roster: " + err.Error())
+		}
+		if err := roster.validate(); err != nil {
+			return Roster{}, errors.New("Error validating roster: " + err.Error())
+		}
+		return roster, nil
+	}
+	return Roster{}, fmt.Errorf("no roster found (expected at %s)", strings.Join(files, " or "))
+}
+
+// parseRosterCSV parses a roster given as CSV with a header naming the columns
+// name, tz, work_start, work_end, weekend and tags (lists are separated by
+// spaces or semicolons, empty cells use the defaults).
+func parseRosterCSV(r io.Reader) (Roster, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Roster{}, err
+	}
+	if len(records) == 0 {
+		return Roster{}, nil
+	}
+	// Map columns by header
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "tz"} {
+		if _, ok := columns[required]; !ok {
+			return Roster{}, fmt.Errorf("missing column: %s", required)
+		}
+	}
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	list := func(value string) []string {
+		return strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ' ' })
+	}
+	// Read people
+	var roster Roster
+	for line, record := range records[1:] {
+		p := Person{Name: field(record, "name"), TZ: field(record, "tz")}
+		var err error
+		if p.WorkStart, err = dayTimeField(field(record, "work_start")); err != nil {
+			return Roster{}, fmt.Errorf("line %d: %s", line+2, err)
+		}
+		if p.WorkEnd, err = dayTimeField(field(record, "work_end")); err != nil {
+			return Roster{}, fmt.Errorf("line %d: %s", line+2, err)
+		}
+		// Empty weekends use the default ones, "none" refers to no weekend
+		if weekend := field(record, "weekend"); strings.EqualFold(weekend, "none") {
+			p.Weekend = []string{}
+		} else if weekend != "" {
+			p.Weekend = list(weekend)
+		}
+		p.Tags = list(field(record, "tags"))
+		roster.People = append(roster.People, p)
+	}
+	return roster, nil
+}
+
+// dayTimeField parses an optional time of the day (nil, if empty).
+func dayTimeField(value string) (*DayTime, error) {
+	if value == "" {
+		return nil, nil
+	}
+	dt, err := parseDayTime(value)
+	if err != nil {
+		return nil, err
+	}
+	return &dt, nil
+}
+
+// locations groups the people with any of the given tags (all, if none) by
+// timezone and returns a location per zone named after its people.
+func (r Roster) locations(tags []string) []Location {
+	var zones []string
+	people := map[string][]string{}
+	for _, p := range r.People {
+		if !p.hasTag(tags) {
+			continue
+		}
+		if _, ok := people[p.TZ]; !ok {
+			zones = append(zones, p.TZ)
+		}
+		people[p.TZ] = append(people[p.TZ], p.Name)
+	}
+	locations := make([]Location, len(zones))
+	for i, zone := range zones {
+		names := people[zone]
+		name := strings.Join(names, ", ")
+		if len(names) > rosterGroupLimit {
+			name = fmt.Sprintf("%s +%d", strings.Join(names[:rosterGroupLimit], ", "), len(names)-rosterGroupLimit)
+		}
+		locations[i] = Location{Name: name, TZ: zone}
+	}
+	return locations
+}
+
+// resolveTeamView replaces the configured timezones by the zones of the team,
+// if the team view is desired (the roster is loaded once, the view plots the
+// day around the time afterwards).
+func resolveTeamView(cfg Config) (Config, error) {
+	if cfg.View != ViewTeam {
+		return cfg, nil
+	}
+	roster, err := LoadRoster(cfg)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Timezones = roster.locations(cfg.RosterTags)
+	cfg.View = ViewDay
+	return cfg, nil
+}
+
+// PlotWho plots who of the roster (with any of the configured tags) is
+// working, available or asleep at the given time.
+func PlotWho(plt Plotter, cfg Config, t time.Time) error {
+	roster, err := LoadRoster(cfg)
+	if err != nil {
+		return err
+	}
+	l := getLocale(cfg.Locale)
+	// Group people by status
+	groups := map[string][]Person{}
+	nameWidth, zoneWidth := 0, 0
+	for _, p := range roster.People {
+		if !p.hasTag(cfg.RosterTags) {
+			continue
+		}
+		status, err := p.status(cfg, t)
+		if err != nil {
+			return err
+		}
+		groups[status] = append(groups[status], p)
+		nameWidth = max(nameWidth, textWidth(p.Name))
+		zoneWidth = max(zoneWidth, textWidth(p.TZ))
+	}
+	// Plot groups
+	contexts := map[string]ContextType{
+		StatusWorking:   ContextDay,
+		StatusAvailable: ContextEvening,
+		StatusAsleep:    ContextNight,
+	}
+	for _, status := range []string{StatusWorking, StatusAvailable, StatusAsleep} {
+		people := groups[status]
+		plt.PlotLine(contexts[status], fmt.Sprintf("%s (%d)", status, len(people)))
+		for _, p := range people {
+			loc, _ := loadLocation(p.TZ)
+			tzTime := t.In(loc)
+			plt.PlotLine(ContextNormal, strings.TrimRight("  "+padRight(p.Name, nameWidth)+" "+
+				padRight(p.TZ, zoneWidth)+" "+
+				l.shortDays[tzTime.Weekday()]+" "+formatHeader(cfg, tzTime)+" "+
+				strings.Join(p.Tags, " "), " "))
+		}
+	}
+	return nil
+}
+
+// Who prints who of the roster is working, available or asleep at the given
+// time (now, if zero).
+func Who(c Config, t time.Time) error {
+	plt := newStaticPlotter(c, t.IsZero())
+	if plt.Now {
+		t = time.Now()
+	}
+	return PlotWho(plt, c, t)
+}
@@ -0,0 +1,318 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServeAddrDefault is the default address to serve the API and page at.
+const ServeAddrDefault = ":8080"
+
+// Define serve defaults
+const (
+	// servePageWidth is the default width of the plot shown by the page.
+	servePageWidth = 80
+	// servePageMaxWidth limits the width requested for the page.
+	servePageMaxWidth = 500
+	// serveReadHeaderTimeout limits the time to read the request headers.
+	serveReadHeaderTimeout = 10 * time.Second
+	// serveWriteTimeout limits the time to handle a request.
+	serveWriteTimeout = 30 * time.Second
+	// serveMeetDuration is the default duration of the meetings searched.
+	serveMeetDuration = time.Hour
+	// serveMeetHorizon is the time span searched for meetings.
+	serveMeetHorizon = 24 * time.Hour
+	// serveMeetStep is the granularity of the meetings searched.
+	serveMeetStep = 15 * time.Minute
+)
+
+// apiLocation describes the time of a location.
+type apiLocation struct {
+	// Name of the location.
+	Name string `json:"name"`
+	// TZ is the machine-readable timezone name.
+	TZ string `json:"tz"`
+	// Time is the time in the location (RFC 3339).
+	Time string `json:"time"`
+	// Display is the time as formatted by the configuration.
+	Display string `json:"display"`
+	// Segment is the day segment of the time in the location.
+	Segment ContextType `json:"segment"`
+}
+
+// apiTime describes a time in all locations.
+type apiTime struct {
+	// Time is the time (RFC 3339).
+	Time string `json:"time"`
+	// Locations describe the time in every location.
+	Locations []apiLocation `json:"locations"`
+}
+
+// apiMeeting describes a time span in which all locations are in their day
+// segment.
+type apiMeeting struct {
+	// Start of the span (RFC 3339).
+	Start string `json:"start"`
+	// End of the span (RFC 3339).
+	End string `json:"end"`
+	// Locations describe the start of the span in every location.
+	Locations []apiLocation `json:"locations"`
+}
+
+// apiError describes a failed request.
+type apiError struct {
+	// Error describes what went wrong.
+	Error string `json:"error"`
+}
+
+// describeTime describes the given time in all configured locations.
+func describeTime(c Config, t time.Time) (apiTime, error) {
+	_, locations, err := createTimeInfos(c, t)
+	if err != nil {
+		return apiTime{}, err
+	}
+	described := apiTime{Time: t.Format(time.RFC3339), Locations: make([]apiLocation, len(locations))}
+	for i, location := range locations {
+		tzTime := t.In(location.location)
+		described.Locations[i] = apiLocation{
+			Name:    location.description,
+			TZ:      location.location.String(),
+			Time:    tzTime.Format(time.RFC3339),
+			Display: formatHeader(c, tzTime),
+			Segment: getLocationSegment(c, location, tzTime),
+		}
+	}
+	return described, nil
+}
+
+// findMeetings returns the time spans of at least the given duration within a
+// day from the given time, in which all locations are in their day segment.
+func findMeetings(c Config, from time.Time, duration time.Duration) ([]apiMeeting, error) {
+	_, locations, err := createTimeInfos(c, from)
+	if err != nil {
+		return nil, err
+	}
+	available := func(t time.Time) bool {
+		for _, location := range locations {
+			if getLocationSegment(c, location, t.In(location.location)) != ContextDay {
+				return false
+			}
+		}
+		return true
+	}
+	// Collect the maximal spans of available steps (starting at the next step)
+	start := from.Truncate(serveMeetStep)
+	if start.Before(from) {
+		start = start.Add(serveMeetStep)
+	}
+	meetings := []apiMeeting{}
+	var spanStart time.Time
+	for t := start; !t.After(start.Add(serveMeetHorizon)); t = t.Add(serveMeetStep) {
+		ok := available(t) && t.Before(start.Add(serveMeetHorizon))
+		if ok && spanStart.IsZero() {
+			spanStart = t
+		}
+		if ok || spanStart.IsZero() {
+			continue
+		}
+		if t.Sub(spanStart) >= duration {
+			described, err := describeTime(c, spanStart)
+			if err != nil {
+				return nil, err
+			}
+			meetings = append(meetings, apiMeeting{
+				Start:     spanStart.Format(time.RFC3339),
+				End:       t.Format(time.RFC3339),
+				Locations: described.Locations,
+			})
+		}
+		spanStart = time.Time{}
+	}
+	return meetings, nil
+}
+
+// writeJSON writes the given value as JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// requestedTime returns the time given by the query parameter (using the
+// request time format) or the current one.
+func requestedTime(c Config, r *http.Request, clock func() time.Time) (time.Time, error) {
+	if value := r.URL.Query().Get("time"); value != "" {
+		return ParseRequestTime(c, value)
+	}
+	return clock(), nil
+}
+
+// renderHTML renders the plot of the given time as HTML page refreshing itself
+// (at the precision of the clock shown).
+func renderHTML(c Config, t time.Time, width int) (string, error) {
+	// Determine the colors of all contexts (named and hex colors are valid in CSS
+	// too)
+	colors := map[ContextType]string{
+		ContextNormal:   c.Style.Coloring.DynamicColorForeground,
+		ContextMorning:  c.Style.Coloring.DynamicColorMorning,
+		ContextDay:      c.Style.Coloring.DynamicColorDay,
+		ContextEvening:  c.Style.Coloring.DynamicColorEvening,
+		ContextNight:    c.Style.Coloring.DynamicColorNight,
+		ContextMidnight: c.Style.Coloring.DynamicColorMidnight,
+		ContextEvent:    c.Style.Coloring.DynamicColorEvent,
+	}
+	for ctx, rgb := range gradientColors(c.Style.Coloring.GradientPalette) {
+		colors[ctx] = fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+	}
+	// Plot into spans colored by context (merging neighboring ones of the same
+	// color)
+	sb := strings.Builder{}
+	open := ""
+	span := func(ctx ContextType, msg string) {
+		color := ""
+		if ctx != ContextNormal {
+			color = strings.ToLower(colors[ctx])
+		}
+		if color != open {
+			if open != "" {
+				sb.WriteString("</span>")
+			}
+			if color != "" {
+				sb.WriteString(`<span style="color:` + html.EscapeString(color) + `">`)
+			}
+			open = color
+		}
+		sb.WriteString(html.EscapeString(msg))
+	}
+	plt := Plotter{
+		Now:           true,
+		TerminalWidth: width,
+		PlotLine: func(ctx ContextType, line ...interface{}) {
+			span(ctx, fmt.Sprint(line...))
+			span(ContextNormal, "\n")
+		},
+		PlotString: span,
+		Symbols:    GetSymbols(c.Style),
+	}
+	if err := PlotTime(plt, c, t); err != nil {
+		return "", err
+	}
+	// Compile page
+	foreground, background := "inherit", "inherit"
+	if c.Style.Coloring.DynamicColorForeground != "" {
+		foreground = strings.ToLower(c.Style.Coloring.DynamicColorForeground)
+	}
+	if c.Style.Coloring.DynamicColorBackground != "" {
+		background = strings.ToLower(c.Style.Coloring.DynamicColorBackground)
+	}
+	return "<!DOCTYPE html>\n" +
+		"<html>\n<head>\n" +
+		"<meta charset=\"utf-8\">\n" +
+		fmt.Sprintf("<meta http-equiv=\"refresh\" content=\"%d\">\n", int(refreshInterval(c.Precision).Seconds())) +
+		"<title>gotz</title>\n" +
+		fmt.Sprintf("<style>body { color: %s; background: %s; } pre { font-size: 1.5em; }</style>\n",
+			html.EscapeString(foreground), html.EscapeString(background)) +
+		"</head>\n<body>\n<pre>\n" + sb.String() + "</pre>\n</body>\n</html>\n", nil
+}
+
+// NewServeHandler returns the handler serving the API and the page for the
+// given configuration (the team of the team view is loaded once). The clock
+// function is used to determine the current time.
+func NewServeHandler(c Config, clock func() time.Time) (http.Handler, error) {
+	c, err := resolveTeamView(c)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/now", func(w http.ResponseWriter, r *http.Request) {
+		described, err := describeTime(c, clock())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apiError{err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, described)
+	})
+	mux.HandleFunc("/api/at", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("time") == "" {
+			writeJSON(w, http.StatusBadRequest, apiError{"missing time (e.g. /api/at?time=15@Asia/Tokyo)"})
+			return
+		}
+		t, err := requestedTime(c, r, clock)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{err.Error()})
+			return
+		}
+		described, err := describeTime(c, t)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apiError{err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, described)
+	})
+	mux.HandleFunc("/api/meet", func(w http.ResponseWriter, r *http.Request) {
+		from, err := requestedTime(c, r, clock)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{err.Error()})
+			return
+		}
+		duration := serveMeetDuration
+		if value := r.URL.Query().Get("duration"); value != "" {
+			duration, err = time.ParseDuration(value)
+			if err != nil || duration <= 0 {
+				writeJSON(w, http.StatusBadRequest, apiError{"invalid duration: " + value})
+				return
+			}
+		}
+		meetings, err := findMeetings(c, from, duration)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, apiError{err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, meetings)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		width := servePageWidth
+		if value := r.URL.Query().Get("width"); value != "" {
+			if w, err := strconv.Atoi(value); err == nil && w > 0 {
+				width = min(w, servePageMaxWidth)
+			}
+		}
+		page, err := renderHTML(c, clock(), width)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	})
+	return mux, nil
+}
+
+// Serve serves the API and the page at the configured address (blocking).
+func Serve(c Config) error {
+	addr := c.ServeAddr
+	if addr == "" {
+		addr = ServeAddrDefault
+	}
+	handler, err := NewServeHandler(c, time.Now)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("serving at %s (page at /, API at /api/now, /api/at and /api/meet)\n", addr)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+		WriteTimeout:      serveWriteTimeout,
+	}
+	return server.ListenAndServe()
+}
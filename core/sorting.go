@@ -31,7 +31,12 @@ func isValidSortingMode(mode string) bool {
 type locationContainer struct {
 	location    *time.Location
 	description string
-	offset      int
+	// Short name of the location (optional)
+	abbreviation string
+	offset       int
+	coordinates  Coordinates
+	// Indicates whether the coordinates are known
+	hasCoordinates bool
 }
 
 // sortLocations sorts the given locations based on the given sorting mode.
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// Define status bars
+const (
+	// StatusBarWaybar streams JSON objects with text, tooltip, class and
+	// percentage (one per line).
+	StatusBarWaybar = "waybar"
+	// StatusBarI3bar streams the i3bar protocol (header and infinite array of
+	// block arrays).
+	StatusBarI3bar = "i3bar"
+	// StatusBarPolybar streams plain text lines (for tailed script modules).
+	StatusBarPolybar = "polybar"
+)
+
+// checkStatusBar checks if the given status bar is valid (empty refers to
+// none).
+func checkStatusBar(bar string) error {
+	switch bar {
+	case "", StatusBarWaybar, StatusBarI3bar, StatusBarPolybar:
+		return nil
+	default:
+		return fmt.Errorf("invalid status bar: %s", bar)
+	}
+}
+
+// statusBarTooltipWidth is the width of the plot shown in the tooltip.
+const statusBarTooltipWidth = 72
+
+// statusUpdate contains the information of a single status bar update.
+type statusUpdate struct {
+	// Text is the compact one-line output.
+	Text string
+	// Tooltip is the full plot.
+	Tooltip string
+	// Class is the day segment of the local timezone.
+	Class ContextType
+	// Percentage is the share of locations in their day segment.
+	Percentage int
+}
+
+// renderText renders the output of the configuration at the given time as
+// plain text (no colors).
+func renderText(c Config, t time.Time, width int) (string, error) {
+	sb := strings.Builder{}
+	plt := Plotter{
+		Now:           true,
+		TerminalWidth: width,
+		PlotLine: func(_ ContextType, line ...interface{}) {
+			sb.WriteString(fmt.Sprint(line...) + "\n")
+		},
+		PlotString: func(_ ContextType, msg string) {
+			sb.WriteString(msg)
+		},
+		Symbols: GetSymbols(c.Style),
+	}
+	err := PlotTime(plt, c, t)
+	return strings.TrimRight(sb.String(), "\n"), err
+}
+
+// createStatusUpdate creates the status bar update for the given time.
+func createStatusUpdate(c Config, t time.Time) (statusUpdate, error) {
+	update := statusUpdate{Class: ContextNormal}
+	// Render compact text and full plot
+	line, plot := c, c
+	line.Output, plot.Output = OutputLine, OutputPlot
+	var err error
+	if update.Text, err = renderText(line, t, statusBarTooltipWidth); err != nil {
+		return update, err
+	}
+	if update.Tooltip, err = renderText(plot, t, statusBarTooltipWidth); err != nil {
+		return update, err
+	}
+	// Determine class and percentage by the day segments
+	_, locations, err := createTimeInfos(c, t)
+	if err != nil {
+		return update, err
+	}
+	available := 0
+	for _, location := range locations {
+		segment := getLocationSegment(c, location, t.In(location.location))
+		if location.location == time.Local {
+			update.Class = segment
+		}
+		if segment == ContextDay {
+			available++
+		}
+	}
+	update.Percentage = int(math.Round(100 * float64(available) / float64(len(locations))))
+	return update, nil
+}
+
+// markupEscaper escapes text for Pango markup (as used by waybar).
+var markupEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// writeStatusUpdate writes the update in the format of the given status bar.
+func writeStatusUpdate(w io.Writer, bar string, update statusUpdate, first bool) error {
+	switch bar {
+	case StatusBarWaybar:
+		data, err := json.Marshal(struct {
+			Text       string `json:"text"`
+			Tooltip    string `json:"tooltip"`
+			Class      string `json:"class"`
+			Percentage int    `json:"percentage"`
+		}{
+			Text:       markupEscaper.Replace(update.Text),
+			Tooltip:    markupEscaper.Replace(update.Tooltip),
+			Class:      string(update.Class),
+			Percentage: update.Percentage,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case StatusBarI3bar:
+		data, err := json.Marshal([]struct {
+			Name     string `json:"name"`
+			Instance string `json:"instance"`
+			FullText string `json:"full_text"`
+		}{{
+			Name:     "gotz",
+			Instance: string(update.Class),
+			FullText: update.Text,
+		}})
+		if err != nil {
+			return err
+		}
+		// Start the protocol with the header and the infinite array
+		if first {
+			if _, err := fmt.Fprintln(w, `{"version":1}`); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(w, "[")
+			if err != nil {
+				return err
+			}
+		} else {
+			data = append([]byte(","), data...)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case StatusBarPolybar:
+		_, err := fmt.Fprintln(w, update.Text)
+		return err
+	default:
+		return fmt.Errorf("invalid status bar: %s", bar)
+	}
+}
+
+// StreamStatusBar continuously writes status bar updates to the given writer
+// (at the precision of the clock shown). It returns when the context is
+// cancelled. The clock function is used to determine the current time.
+func StreamStatusBar(ctx context.Context, w io.Writer, c Config, clock func() time.Time) error {
+	// Load the team once (instead of on every update), if desired
+	c, err := resolveTeamView(c)
+	if err != nil {
+		return err
+	}
+	interval := refreshInterval(c.Precision)
+	for first := true; ; first = false {
+		// Write update
+		now := clock()
+		update, err := createStatusUpdate(c, now)
+		if err != nil {
+			return err
+		}
+		if err := writeStatusUpdate(w, c.StatusBar, update, first); err != nil {
+			return err
+		}
+		// Wait for the next refresh
+		timer := time.NewTimer(nextRefresh(now, interval).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
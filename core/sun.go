@@ -0,0 +1,287 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Define segmentation modes
+const (
+	// SegmentationModeClock segments the day by the configured times of the
+	// day.
+	SegmentationModeClock = "clock"
+	// SegmentationModeSun segments the day by the actual position of the sun
+	// (requires coordinates, falls back to clock mode otherwise).
+	SegmentationModeSun = "sun"
+	// SegmentationModeDefault is the default segmentation mode.
+	SegmentationModeDefault = SegmentationModeClock
+)
+
+// checkSegmentationMode checks if the given segmentation mode is valid (empty
+// refers to the default).
+func checkSegmentationMode(mode string) error {
+	switch mode {
+	case "", SegmentationModeClock, SegmentationModeSun:
+		return nil
+	default:
+		return fmt.Errorf("invalid segmentation mode: %s", mode)
+	}
+}
+
+// Define sun elevations (in degrees) separating the segments.
+const (
+	// sunriseElevation is the elevation of the sun's center at sunrise and
+	// sunset (accounting for refraction and the sun's radius).
+	sunriseElevation = -0.833
+	// civilTwilightElevation is the elevation of the sun at civil dawn and
+	// dusk.
+	civilTwilightElevation = -6.0
+)
+
+// Coordinates defines a geographic position.
+type Coordinates struct {
+	// Latitude in degrees (north is positive).
+	Latitude float64
+	// Longitude in degrees (east is positive).
+	Longitude float64
+}
+
+// checkCoordinates checks if the given coordinates are within range.
+func checkCoordinates(c Coordinates) error {
+	if c.Latitude < -90 || c.Latitude > 90 || c.Longitude < -180 || c.Longitude > 180 {
+		return fmt.Errorf("invalid coordinates: %g,%g", c.Latitude, c.Longitude)
+	}
+	return nil
+}
+
+// resolveCoordinates returns the coordinates of the location. Explicitly
+// configured coordinates take precedence over the built-in city table.
+func resolveCoordinates(l Location) (Coordinates, bool) {
+	if l.Latitude != nil && l.Longitude != nil {
+		return Coordinates{*l.Latitude, *l.Longitude}, true
+	}
+	c, ok := cityCoordinates[l.TZ]
+	return c, ok
+}
+
+// localCoordinates returns the coordinates of the local timezone, if it can be
+// determined and is part of the built-in city table.
+func localCoordinates() (Coordinates, bool) {
+	c, ok := cityCoordinates[localZoneName()]
+	return c, ok
+}
+
+// localZoneName returns the IANA identifier of the local timezone, if it can be
+// determined (empty otherwise).
+func localZoneName() string {
+	if name := time.Local.String(); name != "Local" {
+		return name
+	}
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); tz != "" {
+		return tz
+	}
+	if target, err := filepath.EvalSymlinks("/etc/localtime"); err == nil {
+		if _, name, found := strings.Cut(target, "zoneinfo/"); found {
+			return name
+		}
+	}
+	return ""
+}
+
+// radians converts degrees to radians.
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// degrees converts radians to degrees.
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// sunPosition returns the elevation of the sun (in degrees) and its hour angle
+// (in degrees, negative before solar noon) at the given time and coordinates.
+// It follows the NOAA solar calculations, which are accurate to about a minute
+// for sunrise and sunset times.
+func sunPosition(t time.Time, c Coordinates) (elevation, hourAngle float64) {
+	// Julian century
+	t = t.UTC()
+	jd := float64(t.Unix())/86400 + 2440587.5
+	jc := (jd - 2451545) / 36525
+	// Sun's geometry
+	meanLong := math.Mod(280.46646+jc*(36000.76983+jc*0.0003032), 360)
+	meanAnom := 357.52911 + jc*(35999.05029-0.0001537*jc)
+	eccent := 0.016708634 - jc*(0.000042037+0.0000001267*jc)
+	center := math.Sin(radians(meanAnom))*(1.914602-jc*(0.004817+0.000014*jc)) +
+		math.Sin(radians(2*meanAnom))*(0.019993-0.000101*jc) +
+		math.Sin(radians(3*meanAnom))*0.000289
+	appLong := meanLong + center - 0.00569 - 0.00478*math.Sin(radians(125.04-1934.136*jc))
+	meanObliq := 23 + (26+(21.448-jc*(46.815+jc*(0.00059-jc*0.001813)))/60)/60
+	obliq := meanObliq + 0.00256*math.Cos(radians(125.04-1934.136*jc))
+	declination := degrees(math.Asin(math.Sin(radians(obliq)) * math.Sin(radians(appLong))))
+	// Equation of time (in minutes)
+	y := math.Pow(math.Tan(radians(obliq/2)), 2)
+	eqTime := 4 * degrees(y*math.Sin(2*radians(meanLong))-
+		2*eccent*math.Sin(radians(meanAnom))+
+		4*eccent*y*math.Sin(radians(meanAnom))*math.Cos(2*radians(meanLong))-
+		0.5*y*y*math.Sin(4*radians(meanLong))-
+		1.25*eccent*eccent*math.Sin(2*radians(meanAnom)))
+	// Hour angle
+	minutes := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60
+	trueSolarTime := math.Mod(minutes+eqTime+4*c.Longitude, 1440)
+	if trueSolarTime < 0 {
+		trueSolarTime += 1440
+	}
+	hourAngle = trueSolarTime/4 - 180
+	// Elevation
+	cosZenith := math.Sin(radians(c.Latitude))*math.Sin(radians(declination)) +
+		math.Cos(radians(c.Latitude))*math.Cos(radians(declination))*math.Cos(radians(hourAngle))
+	zenith := degrees(math.Acos(math.Max(-1, math.Min(1, cosZenith))))
+	return 90 - zenith, hourAngle
+}
+
+// getSunSegment returns the day segment for the given time and coordinates
+// based on the position of the sun: the day lasts from sunrise to sunset,
+// morning and evening are the civil twilights and the rest is night.
+func getSunSegment(t time.Time, c Coordinates) ContextType {
+	elevation, hourAngle := sunPosition(t, c)
+	switch {
+	case elevation >= sunriseElevation:
+		return ContextDay
+	case elevation < civilTwilightElevation:
+		return ContextNight
+	case hourAngle < 0:
+		return ContextMorning
+	default:
+		return ContextEvening
+	}
+}
+
+// getLightLevel returns the light level (from 0 - night to 1 - day) at the
+// given time. It is based on the sun's elevation, if the coordinates are known,
+// and on the time of the day otherwise.
+func getLightLevel(t time.Time, c Coordinates, hasCoordinates bool) float64 {
+	if hasCoordinates {
+		// Scale from nautical twilight to well above the horizon
+		elevation, _ := sunPosition(t, c)
+		return math.Max(0, math.Min(1, (elevation+12)/42))
+	}
+	// Follow a cosine from midnight (0) to noon (1)
+	minutes := float64(dayTimeOf(t)) + float64(t.Second())/60
+	return (1 - math.Cos(2*math.Pi*minutes/MinutesPerDay)) / 2
+}
+
+// cityCoordinates maps IANA timezone identifiers to the coordinates of their
+// principal city.
+var cityCoordinates = map[string]Coordinates{
+	// Africa
+	"Africa/Abidjan":       {5.32, -4.03},
+	"Africa/Accra":         {5.56, -0.20},
+	"Africa/Addis_Ababa":   {9.03, 38.74},
+	"Africa/Algiers":       {36.75, 3.06},
+	"Africa/Cairo":         {30.04, 31.24},
+	"Africa/Casablanca":    {33.57, -7.59},
+	"Africa/Dar_es_Salaam": {-6.79, 39.21},
+	"Africa/Johannesburg":  {-26.20, 28.05},
+	"Africa/Khartoum":      {15.50, 32.56},
+	"Africa/Kinshasa":      {-4.44, 15.27},
+	"Africa/Lagos":         {6.52, 3.38},
+	"Africa/Nairobi":       {-1.29, 36.82},
+	"Africa/Tunis":         {36.81, 10.18},
+	// America
+	"America/Anchorage":              {61.22, -149.90},
+	"America/Argentina/Buenos_Aires": {-34.60, -58.38},
+	"America/Bogota":                 {4.71, -74.07},
+	"America/Caracas":                {10.48, -66.90},
+	"America/Chicago":                {41.88, -87.63},
+	"America/Denver":                 {39.74, -104.99},
+	"America/Edmonton":               {53.55, -113.49},
+	"America/Halifax":                {44.65, -63.58},
+	"America/Havana":                 {23.11, -82.37},
+	"America/Lima":                   {-12.05, -77.04},
+	"America/Los_Angeles":            {34.05, -118.24},
+	"America/Mexico_City":            {19.43, -99.13},
+	"America/Montevideo":             {-34.90, -56.16},
+	"America/New_York":               {40.71, -74.01},
+	"America/Panama":                 {8.98, -79.52},
+	"America/Phoenix":                {33.45, -112.07},
+	"America/Santiago":               {-33.45, -70.67},
+	"America/Sao_Paulo":              {-23.55, -46.63},
+	"America/St_Johns":               {47.56, -52.71},
+	"America/Toronto":                {43.65, -79.38},
+	"America/Vancouver":              {49.28, -123.12},
+	"America/Winnipeg":               {49.90, -97.14},
+	// Antarctica and Arctic
+	"Antarctica/McMurdo":  {-77.85, 166.67},
+	"Arctic/Longyearbyen": {78.22, 15.65},
+	// Asia
+	"Asia/Almaty":        {43.24, 76.89},
+	"Asia/Baghdad":       {33.32, 44.36},
+	"Asia/Bangkok":       {13.76, 100.50},
+	"Asia/Colombo":       {6.93, 79.86},
+	"Asia/Dhaka":         {23.81, 90.41},
+	"Asia/Dubai":         {25.20, 55.27},
+	"Asia/Ho_Chi_Minh":   {10.82, 106.63},
+	"Asia/Hong_Kong":     {22.32, 114.17},
+	"Asia/Jakarta":       {-6.21, 106.85},
+	"Asia/Jerusalem":     {31.77, 35.21},
+	"Asia/Kabul":         {34.56, 69.21},
+	"Asia/Karachi":       {24.86, 67.01},
+	"Asia/Kathmandu":     {27.72, 85.32},
+	"Asia/Kolkata":       {22.57, 88.36},
+	"Asia/Kuala_Lumpur":  {3.14, 101.69},
+	"Asia/Manila":        {14.60, 120.98},
+	"Asia/Riyadh":        {24.71, 46.68},
+	"Asia/Seoul":         {37.57, 126.98},
+	"Asia/Shanghai":      {31.23, 121.47},
+	"Asia/Singapore":     {1.35, 103.82},
+	"Asia/Taipei":        {25.03, 121.57},
+	"Asia/Tehran":        {35.69, 51.39},
+	"Asia/Tokyo":         {35.68, 139.69},
+	"Asia/Vladivostok":   {43.12, 131.89},
+	"Asia/Yangon":        {16.87, 96.20},
+	"Asia/Yekaterinburg": {56.84, 60.61},
+	// Atlantic
+	"Atlantic/Azores":    {37.74, -25.68},
+	"Atlantic/Reykjavik": {64.15, -21.94},
+	// Australia
+	"Australia/Adelaide":  {-34.93, 138.60},
+	"Australia/Brisbane":  {-27.47, 153.03},
+	"Australia/Darwin":    {-12.46, 130.84},
+	"Australia/Hobart":    {-42.88, 147.33},
+	"Australia/Melbourne": {-37.81, 144.96},
+	"Australia/Perth":     {-31.95, 115.86},
+	"Australia/Sydney":    {-33.87, 151.21},
+	// Europe
+	"Europe/Amsterdam":  {52.37, 4.90},
+	"Europe/Athens":     {37.98, 23.73},
+	"Europe/Belgrade":   {44.79, 20.45},
+	"Europe/Berlin":     {52.52, 13.40},
+	"Europe/Brussels":   {50.85, 4.35},
+	"Europe/Bucharest":  {44.43, 26.10},
+	"Europe/Budapest":   {47.50, 19.04},
+	"Europe/Copenhagen": {55.68, 12.57},
+	"Europe/Dublin":     {53.35, -6.26},
+	"Europe/Helsinki":   {60.17, 24.94},
+	"Europe/Istanbul":   {41.01, 28.98},
+	"Europe/Kyiv":       {50.45, 30.52},
+	"Europe/Lisbon":     {38.72, -9.14},
+	"Europe/London":     {51.51, -0.13},
+	"Europe/Madrid":     {40.42, -3.70},
+	"Europe/Moscow":     {55.76, 37.62},
+	"Europe/Oslo":       {59.91, 10.75},
+	"Europe/Paris":      {48.86, 2.35},
+	"Europe/Prague":     {50.08, 14.44},
+	"Europe/Rome":       {41.90, 12.50},
+	"Europe/Stockholm":  {59.33, 18.07},
+	"Europe/Vienna":     {48.21, 16.37},
+	"Europe/Warsaw":     {52.23, 21.01},
+	"Europe/Zurich":     {47.38, 8.54},
+	// Pacific
+	"Pacific/Auckland": {-36.85, 174.76},
+	"Pacific/Fiji":     {-18.14, 178.44},
+	"Pacific/Honolulu": {21.31, -157.86},
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// formatDefault is the value resetting a format to its default.
+const formatDefault = "default"
+
+// isStrftimeFormat indicates whether the given format is a strftime-style
+// format (i.e., contains '%' verbs) instead of a Go layout.
+func isStrftimeFormat(format string) bool {
+	return strings.Contains(format, "%")
+}
+
+// checkTimeFormat checks if the given custom time format is valid (empty
+// refers to the default).
+func checkTimeFormat(format string) error {
+	if !isStrftimeFormat(format) {
+		// Any Go layout is valid (unknown parts are kept as they are)
+		return nil
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i+1 >= len(format) {
+			return fmt.Errorf("invalid time format: %s (dangling %%)", format)
+		}
+		i++
+		if _, ok := strftimeVerbs[format[i]]; !ok {
+			return fmt.Errorf("invalid time format: %s (unknown verb %%%c)", format, format[i])
+		}
+	}
+	return nil
+}
+
+// formatCustom formats the time using the given Go layout or strftime-style
+// format (using the names of the given locale).
+func formatCustom(format string, t time.Time, l locale) string {
+	if isStrftimeFormat(format) {
+		return strftime(format, t, l)
+	}
+	return formatLayout(format, t, l)
+}
+
+// strftimeVerbs maps all supported strftime verbs to their implementation.
+var strftimeVerbs = map[byte]func(t time.Time, l locale) string{
+	// Date
+	'Y': func(t time.Time, l locale) string { return fmt.Sprintf("%04d", t.Year()) },
+	'y': func(t time.Time, l locale) string { return fmt.Sprintf("%02d", t.Year()%100) },
+	'C': func(t time.Time, l locale) string { return fmt.Sprintf("%02d", t.Year()/100) },
+	'm': func(t time.Time, l locale) string { return fmt.Sprintf("%02d", int(t.Month())) },
+	'd': func(t time.Time, l locale) string { return fmt.Sprintf("%02d", t.Day()) },
+	'e': func(t time.Time, l locale) string { return fmt.Sprintf("%2d", t.Day()) },
+	'j': func(t time.Time, l locale) string { return fmt.Sprintf("%03d", t.YearDay()) },
+	'a': func(t time.Time, l locale) string { return l.shortDays[t.Weekday()] },
+	'A': func(t time.Time, l locale) string { return l.days[t.Weekday()] },
+	'b': func(t time.Time, l locale) string { return l.shortMonths[t.Month()-1] },
+	'h': func(t time.Time, l locale) string { return l.shortMonths[t.Month()-1] },
+	'B': func(t time.Time, l locale) string { return l.months[t.Month()-1] },
+	'u': func(t time.Time, l locale) string { return fmt.Sprintf("%d", (int(t.Weekday())+6)%7+1) },
+	'w': func(t time.Time, l locale) string { return fmt.Sprintf("%d", int(t.Weekday())) },
+	// Weeks
+	'V': func(t time.Time, l locale) string { _, w := t.ISOWeek(); return fmt.Sprintf("%02d", w) },
+	'G': func(t time.Time, l locale) string { y, _ := t.ISOWeek(); return fmt.Sprintf("%04d", y) },
+	'U': func(t time.Time, l locale) string { return fmt.Sprintf("%02d", (t.YearDay()+6-int(t.Weekday()))/7) },
+	'W': func(t time.Time, l locale) string {
+		return fmt.Sprintf("%02d", (t.YearDay()+6-(int(t.Weekday())+6)%7)/7)
+	},
+	// Time
+	'H': func(t time.Time, l locale) string { return fmt.Sprintf("%02d", t.Hour()) },
+	'k': func(t time.Time, l locale) string { return fmt.Sprintf("%2d", t.Hour()) },
+	'I': func(t time.Time, l locale) string { return fmt.Sprintf("%02d", (t.Hour()+11)%12+1) },
+	'l': func(t time.Time, l locale) string { return fmt.Sprintf("%2d", (t.Hour()+11)%12+1) },
+	'M': func(t time.Time, l locale) string { return fmt.Sprintf("%02d", t.Minute()) },
+	'S': func(t time.Time, l locale) string { return fmt.Sprintf("%02d", t.Second()) },
+	'p': func(t time.Time, l locale) string { return l.meridiem(t) },
+	'P': func(t time.Time, l locale) string { return strings.ToLower(l.meridiem(t)) },
+	// Zone
+	'z': func(t time.Time, l locale) string { return t.Format("-0700") },
+	'Z': func(t time.Time, l locale) string { return t.Format("MST") },
+	's': func(t time.Time, l locale) string { return fmt.Sprintf("%d", t.Unix()) },
+	// Combinations
+	'F': func(t time.Time, l locale) string { return t.Format("2006-01-02") },
+	'T': func(t time.Time, l locale) string { return t.Format("15:04:05") },
+	'R': func(t time.Time, l locale) string { return t.Format("15:04") },
+	'D': func(t time.Time, l locale) string { return t.Format("01/02/06") },
+	// Literals (newlines and tabs would break the layout of the plot, hence
+	// they are written as spaces)
+	'n': func(t time.Time, l locale) string { return " " },
+	't': func(t time.Time, l locale) string { return " " },
+	'%': func(t time.Time, l locale) string { return "%" },
+}
+
+// strftime formats the time using a strftime-style format. Unknown verbs are
+// kept as they are.
+func strftime(format string, t time.Time, l locale) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 >= len(format) {
+			sb.WriteByte(format[i])
+			continue
+		}
+		i++
+		if f, ok := strftimeVerbs[format[i]]; ok {
+			sb.WriteString(f(t, l))
+		} else {
+			sb.WriteByte('%')
+			sb.WriteByte(format[i])
+		}
+	}
+	return sb.String()
+}
+
+// formatInfo formats the time shown in the time info column of a location.
+func formatInfo(cfg Config, t time.Time) string {
+	l := getLocale(cfg.Locale)
+	if cfg.Formats.Info != "" {
+		return formatCustom(cfg.Formats.Info, t, l)
+	}
+	return formatDay(l, t) + " " + formatTime(l, cfg.Hours12, true, cfg.Precision, t)
+}
+
+// formatHeader formats the time shown in the header.
+func formatHeader(cfg Config, t time.Time) string {
+	l := getLocale(cfg.Locale)
+	if cfg.Formats.Header != "" {
+		return formatCustom(cfg.Formats.Header, t, l)
+	}
+	return formatTime(l, cfg.Hours12, false, cfg.Precision, t)
+}
+
+// formatTic formats the label of a tic (the time given is a full hour).
+func formatTic(cfg Config, t time.Time) string {
+	l := getLocale(cfg.Locale)
+	if cfg.Formats.Tics != "" {
+		return formatCustom(cfg.Formats.Tics, t, l)
+	}
+	if cfg.Hours12 {
+		return formatLayout("3PM", t, l)
+	}
+	return fmt.Sprintf("%d", t.Hour())
+}
+
+// textWidth returns the number of terminal cells needed to display the text.
+func textWidth(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// padRight pads the text with spaces to the given number of terminal cells.
+func padRight(s string, width int) string {
+	if w := textWidth(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// padLeft pads the text with leading spaces to the given number of terminal
+// cells.
+func padLeft(s string, width int) string {
+	if w := textWidth(s); w < width {
+		return strings.Repeat(" ", width-w) + s
+	}
+	return s
+}
+
+// truncate truncates the text to the given number of terminal cells.
+func truncate(s string, width int) string {
+	return runewidth.Truncate(s, width, "")
+}
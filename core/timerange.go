@@ -0,0 +1,120 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Define range symbols
+const (
+	// RangeStartSymbol marks the slot before a requested time range.
+	RangeStartSymbol = "["
+	// RangeEndSymbol marks the slot after a requested time range.
+	RangeEndSymbol = "]"
+	// RangeRowSymbol marks the rows within a requested time range (vertical
+	// layout).
+	RangeRowSymbol = "┃"
+)
+
+// rangeContextPrefix prefixes the contexts of slots within a requested time
+// range (e.g. "range-day").
+const rangeContextPrefix = "range-"
+
+// TimeRange is a requested span of time (e.g. a meeting).
+type TimeRange struct {
+	// Start of the range.
+	Start time.Time
+	// End of the range (exclusive, zero if no range was requested).
+	End time.Time
+}
+
+// IsZero indicates whether no range was requested.
+func (r TimeRange) IsZero() bool {
+	return r.End.IsZero()
+}
+
+// overlaps indicates whether the range overlaps with the given slot.
+func (r TimeRange) overlaps(slot timeslot) bool {
+	return !r.IsZero() && r.Start.Before(slot.Time.Add(slot.Length)) && r.End.After(slot.Time)
+}
+
+// ParseRequestRange parses a requested time or time range (<start>-<end>, e.g.
+// 15:00-16:30@2) in the formats of requested times. Ends without a date refer
+// to the first time after the start. For single times, the end is zero.
+func ParseRequestRange(config Config, t string) (TimeRange, error) {
+	value, tz, err := splitRequestTZ(config, t)
+	if err != nil {
+		return TimeRange{}, err
+	}
+	// Handle single times
+	start, _, err := parseTimeFormat(value, tz)
+	if err == nil {
+		return TimeRange{Start: start}, nil
+	}
+	// Handle ranges (dates contain dashes too, hence, try all of them)
+	for i := 0; i < len(value); i++ {
+		if value[i] != '-' {
+			continue
+		}
+		start, _, startErr := parseTimeFormat(value[:i], tz)
+		end, endFormat, endErr := parseTimeFormat(value[i+1:], tz)
+		if startErr != nil || endErr != nil {
+			continue
+		}
+		if !endFormat.Date && !endFormat.TZInfo {
+			// Use the first time of the day after the start
+			s := start.In(tz)
+			end = time.Date(s.Year(), s.Month(), s.Day(), end.Hour(), end.Minute(), end.Second(), 0, tz)
+			if !end.After(start) {
+				end = end.AddDate(0, 0, 1)
+			}
+		}
+		if !end.After(start) {
+			return TimeRange{}, fmt.Errorf("invalid time range: %s (end is not after start)", t)
+		}
+		return TimeRange{Start: start, End: end}, nil
+	}
+	return TimeRange{}, err
+}
+
+// rangeContext returns the context of a slot of the given context within a
+// requested time range.
+func rangeContext(ctx ContextType) ContextType {
+	return ContextType(rangeContextPrefix + string(ctx))
+}
+
+// addStaticRangeColors adds the colors of slots within a requested time range
+// (the colors of their contexts, reversed) to the given map.
+func addStaticRangeColors(colorMap map[ContextType]string) {
+	// Collect the contexts first (adding keys while ranging over the map may
+	// visit the new ones too)
+	contexts := make([]ContextType, 0, len(colorMap))
+	for ctx := range colorMap {
+		contexts = append(contexts, ctx)
+	}
+	for _, ctx := range contexts {
+		colorMap[rangeContext(ctx)] = colorMap[ctx] + "\u001b[7m"
+	}
+}
+
+// addDynamicRangeColors adds the styles of slots within a requested time range
+// (the styles of their contexts, reversed) to the given map.
+func addDynamicRangeColors(colorMap map[ContextType]tcell.Style) {
+	// Collect the contexts first (see addStaticRangeColors)
+	contexts := make([]ContextType, 0, len(colorMap))
+	for ctx := range colorMap {
+		contexts = append(contexts, ctx)
+	}
+	for _, ctx := range contexts {
+		colorMap[rangeContext(ctx)] = colorMap[ctx].Reverse(true)
+	}
+}
+
+// formatRangeEnd formats the end of the range in the given location (adding
+// the day difference to the start, if any).
+func formatRangeEnd(cfg Config, r TimeRange, location *time.Location) string {
+	end := r.End.In(location)
+	return formatHeader(cfg, end) + formatDayDifference(end, r.Start.In(location), "d")
+}
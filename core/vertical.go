@@ -0,0 +1,174 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Define layouts
+const (
+	// LayoutHorizontal plots one horizontal bar per location.
+	LayoutHorizontal = "horizontal"
+	// LayoutVertical plots one column per location with time flowing
+	// downward (one row per hour).
+	LayoutVertical = "vertical"
+	// LayoutDefault is the default layout.
+	LayoutDefault = LayoutHorizontal
+)
+
+// checkLayout checks if the given layout is valid (empty refers to the
+// default).
+func checkLayout(layout string) error {
+	switch layout {
+	case "", LayoutHorizontal, LayoutVertical:
+		return nil
+	default:
+		return fmt.Errorf("invalid layout: %s", layout)
+	}
+}
+
+// verticalRows is the number of rows (hours) plotted in vertical layout.
+const verticalRows = 24
+
+// plotVertical plots the time with one column per location and one row per
+// hour. The now marker is a horizontal line between the rows.
+func plotVertical(plt Plotter, cfg Config, t time.Time) error {
+	// Get locations (sorted)
+	_, locations, err := createTimeInfos(cfg, t)
+	if err != nil {
+		return err
+	}
+	l := getLocale(cfg.Locale)
+
+	// Prepare rows (the requested time is the start of the now row)
+	span := time.Duration(verticalRows) * time.Hour
+	start := t.Add(-span / 2)
+	nowRow := verticalRows / 2
+	timeSlots := make([]timeslot, verticalRows)
+	axis := make([]string, verticalRows)
+	axisWidth := 0
+	for i := range timeSlots {
+		timeSlots[i] = timeslot{
+			Time:   start.Add(time.Duration(i) * time.Hour),
+			Length: time.Hour,
+		}
+		axis[i] = formatTime(l, cfg.Hours12, true, PrecisionMinutes, timeSlots[i].Time)
+		if w := textWidth(axis[i]); w > axisWidth {
+			axisWidth = w
+		}
+	}
+	nowTag := "now"
+	if !plt.Now {
+		nowTag = "time"
+	}
+	if w := textWidth(nowTag); w > axisWidth {
+		axisWidth = w
+	}
+
+	// Get calendar events within the span
+	events, err := loadEvents(cfg.Calendars, start, start.Add(span))
+	if err != nil {
+		return err
+	}
+	eventSymbol := cfg.Style.EventSymbol
+	if eventSymbol == "" {
+		eventSymbol = EventSymbolDefault
+	}
+
+	// Determine the column widths (dropping columns exceeding the terminal)
+	names := make([]string, len(locations))
+	times := make([]string, len(locations))
+	widths := make([]int, len(locations))
+	lineWidth := axisWidth
+	columns := 0
+	for i, location := range locations {
+		names[i] = location.description
+		times[i] = formatHeader(cfg, t.In(location.location))
+		widths[i] = textWidth(names[i])
+		if w := textWidth(times[i]); w > widths[i] {
+			widths[i] = w
+		}
+		if lineWidth+1+widths[i] > plt.TerminalWidth {
+			break
+		}
+		lineWidth += 1 + widths[i]
+		columns++
+	}
+
+	// Plot header (names and current times)
+	for _, values := range [][]string{names, times} {
+		plt.PlotString(ContextNormal, strings.Repeat(" ", axisWidth))
+		for i := 0; i < columns; i++ {
+			plt.PlotString(ContextNormal, " "+padRight(values[i], widths[i]))
+		}
+		plt.PlotLine(ContextNormal)
+	}
+
+	// Determine the rows of the marks (if any)
+	markRows := markSlots(cfg.Marks, timeSlots)
+
+	// Plot rows
+	midnightSymbol := cfg.Style.MidnightSymbol
+	if midnightSymbol == "" {
+		midnightSymbol = MidnightSymbolDefault
+	}
+	for i, slot := range timeSlots {
+		// Mark now
+		if i == nowRow {
+			plt.PlotLine(ContextNormal, padRight(nowTag, axisWidth)+" "+strings.Repeat("─", max(0, lineWidth-axisWidth-1)))
+		}
+		plt.PlotString(ContextNormal, padLeft(axis[i], axisWidth))
+		for j := 0; j < columns; j++ {
+			plt.PlotString(ContextNormal, " ")
+			symbol, segment := getSlotStyle(plt, cfg, locations[j], slot.Time)
+			// Mark events
+			if eventAt(events, slot) {
+				symbol, segment = eventSymbol, ContextEvent
+			}
+			// Highlight the requested range
+			if cfg.Range.overlaps(slot) {
+				segment = rangeContext(segment)
+			}
+			cell := strings.Repeat(symbol, widths[j])
+			// Mark day changes
+			tzTime := slot.Time.In(locations[j].location)
+			if i > 0 && (cfg.Style.Midnight == MidnightModeMarker || cfg.Style.Midnight == MidnightModeLabel) &&
+				timeSlots[i-1].Time.In(locations[j].location).Day() != tzTime.Day() {
+				label := ""
+				if cfg.Style.Midnight == MidnightModeLabel {
+					label = l.shortDays[tzTime.Weekday()]
+				}
+				if w := textWidth(label); w < widths[j] {
+					cell = label + strings.Repeat(midnightSymbol, widths[j]-w)
+				} else {
+					cell = truncate(label, widths[j])
+				}
+				segment = ContextMidnight
+			}
+			plt.PlotString(segment, cell)
+		}
+		// Mark the requested range and times (right of the row)
+		markers := ""
+		if cfg.Range.overlaps(slot) {
+			markers += RangeRowSymbol
+		}
+		for j, row := range markRows {
+			if row == i {
+				markers += markSymbol(j)
+			}
+		}
+		if markers != "" {
+			plt.PlotString(ContextNormal, " "+markers)
+		}
+		plt.PlotLine(ContextNormal)
+	}
+
+	// Plot the times of the marks
+	plotMarkTimes(plt, cfg, cfg.Marks, locations[:columns], t)
+
+	// Plot legend of upcoming events
+	plotEvents(plt, cfg, events, locations, t)
+
+	return nil
+}
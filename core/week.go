@@ -0,0 +1,160 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Define views
+const (
+	// ViewDay plots the 24 hours around the requested time.
+	ViewDay = "day"
+	// ViewWeek plots a grid of the hours of the week containing the requested
+	// time, showing how many locations are in their day segment.
+	ViewWeek = "week"
+	// ViewTeam plots the day around the requested time for the zones of the
+	// roster (listing the people there instead of the configured locations).
+	ViewTeam = "team"
+	// ViewDefault is the default view.
+	ViewDefault = ViewDay
+)
+
+// checkView checks if the given view is valid (empty refers to the default).
+func checkView(view string) error {
+	switch view {
+	case "", ViewDay, ViewWeek, ViewTeam:
+		return nil
+	default:
+		return fmt.Errorf("invalid view: %s", view)
+	}
+}
+
+// weekCellWidth is the number of terminal cells per hour in the week view.
+const weekCellWidth = 2
+
+// plotWeek plots a grid with one row per day of the week (Monday to Sunday)
+// containing the given time and one cell per hour. Days and hours are given in
+// the timezone of the time (i.e., the reference location). Each cell shows the
+// number of locations in their day segment, followed by the hours at which all
+// of them are.
+func plotWeek(plt Plotter, cfg Config, t time.Time) error {
+	// Get locations
+	_, locations, err := createTimeInfos(cfg, t)
+	if err != nil {
+		return err
+	}
+	l := getLocale(cfg.Locale)
+	ref := t.Location()
+
+	// Determine the days of the week
+	weekStart := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, ref).
+		AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	days := make([]time.Time, 7)
+	labels := make([]string, 7)
+	labelWidth := 0
+	for i := range days {
+		days[i] = weekStart.AddDate(0, 0, i)
+		labels[i] = formatLayout("Mon 02 Jan", days[i], l)
+		if w := textWidth(labels[i]); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	// Plot header (now marker above the hour of the time)
+	nowTag := "now"
+	if !plt.Now {
+		nowTag = "time"
+	}
+	nowColumn := labelWidth + 1 + t.Hour()*weekCellWidth + weekCellWidth - 1
+	headLine := strings.Repeat(" ", max(0, nowColumn-(len(nowTag)+1))) +
+		nowTag + " v " +
+		formatLayout("Mon 02 Jan", t, l) + " " + formatHeader(cfg, t)
+	plt.PlotLine(ContextNormal, truncate(headLine, plt.TerminalWidth))
+
+	// Plot hour labels
+	hourLine := strings.Repeat(" ", labelWidth+1)
+	for h := 0; h < 24; h += 3 {
+		tic := formatTic(cfg, time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), h, 0, 0, 0, ref))
+		hourLine += padRight(tic, 3*weekCellWidth)
+	}
+	plt.PlotLine(ContextNormal, truncate(strings.TrimRight(hourLine, " "), plt.TerminalWidth))
+
+	// Plot days
+	for i, day := range days {
+		plt.PlotString(ContextNormal, padRight(labels[i], labelWidth)+" ")
+		var full []int
+		for h := 0; h < 24; h++ {
+			count := 0
+			slot := time.Date(day.Year(), day.Month(), day.Day(), h, 0, 0, 0, ref)
+			for _, location := range locations {
+				if getLocationSegment(cfg, location, slot.In(location.location)) == ContextDay {
+					count++
+				}
+			}
+			if count == len(locations) {
+				full = append(full, h)
+			}
+			plt.PlotString(heatContext(cfg, count, len(locations)), formatCount(count))
+		}
+		plt.PlotLine(ContextNormal, formatHourRanges(cfg, day, full))
+	}
+
+	// Plot legend
+	names := make([]string, len(locations))
+	for i, location := range locations {
+		names[i] = location.description
+	}
+	legend := fmt.Sprintf("hours of %s, cells: locations in day segment (of %d)", ref.String(), len(locations))
+	plt.PlotLine(ContextNormal, truncate(legend, plt.TerminalWidth))
+	plt.PlotLine(ContextNormal, truncate("locations: "+strings.Join(names, ", "), plt.TerminalWidth))
+
+	return nil
+}
+
+// heatContext returns the context (color) of a week view cell with the given
+// number of locations in their day segment.
+func heatContext(cfg Config, count, total int) ContextType {
+	if cfg.Style.ColorMode == ColorModeGradient {
+		return gradientContext(float64(count) / float64(total))
+	}
+	switch count {
+	case total:
+		return ContextDay
+	case 0:
+		return ContextNight
+	default:
+		return ContextMorning
+	}
+}
+
+// formatCount formats the number of locations shown in a week view cell.
+func formatCount(count int) string {
+	if count == 0 {
+		return padLeft("·", weekCellWidth)
+	}
+	s := strconv.Itoa(count)
+	if len(s) > weekCellWidth {
+		s = "+"
+	}
+	return padLeft(s, weekCellWidth)
+}
+
+// formatHourRanges formats the given (sorted) hours of the day as ranges (e.g.
+// " 14-16 20-21").
+func formatHourRanges(cfg Config, day time.Time, hours []int) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(hours); i++ {
+		// Find end of range
+		j := i
+		for j+1 < len(hours) && hours[j+1] == hours[j]+1 {
+			j++
+		}
+		from := time.Date(day.Year(), day.Month(), day.Day(), hours[i], 0, 0, 0, day.Location())
+		to := time.Date(day.Year(), day.Month(), day.Day(), hours[j]+1, 0, 0, 0, day.Location())
+		sb.WriteString(" " + formatTic(cfg, from) + "-" + formatTic(cfg, to))
+		i = j
+	}
+	return sb.String()
+}
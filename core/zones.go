@@ -0,0 +1,287 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// zoneMeaning is a possible meaning of a timezone abbreviation.
+type zoneMeaning struct {
+	// Offset to UTC in seconds.
+	Offset int
+	// Description of the zone (e.g. "India").
+	Description string
+}
+
+// hours returns the given hours and minutes as seconds.
+func hours(h, m int) int {
+	if h < 0 {
+		m = -m
+	}
+	return h*3600 + m*60
+}
+
+// zoneAbbreviations maps common timezone abbreviations to their offsets. The
+// first meaning of ambiguous abbreviations is used by default (unless a
+// configured location currently uses the abbreviation with another of the
+// offsets).
+var zoneAbbreviations = map[string][]zoneMeaning{
+	// Universal
+	"UTC": {{0, "Coordinated Universal Time"}},
+	"GMT": {{0, "Greenwich Mean Time"}},
+	"Z":   {{0, "Zulu"}},
+	// North America
+	"HST":  {{hours(-10, 0), "Hawaii"}},
+	"AKST": {{hours(-9, 0), "Alaska"}},
+	"AKDT": {{hours(-8, 0), "Alaska (summer)"}},
+	"PST":  {{hours(-8, 0), "US Pacific"}},
+	"PDT":  {{hours(-7, 0), "US Pacific (summer)"}},
+	"MST":  {{hours(-7, 0), "US Mountain"}},
+	"MDT":  {{hours(-6, 0), "US Mountain (summer)"}},
+	"CST":  {{hours(-6, 0), "US Central"}, {hours(8, 0), "China"}, {hours(-5, 0), "Cuba"}},
+	"CDT":  {{hours(-5, 0), "US Central (summer)"}, {hours(-4, 0), "Cuba (summer)"}},
+	"EST":  {{hours(-5, 0), "US Eastern"}},
+	"EDT":  {{hours(-4, 0), "US Eastern (summer)"}},
+	"AST":  {{hours(-4, 0), "Atlantic"}, {hours(3, 0), "Arabia"}},
+	"ADT":  {{hours(-3, 0), "Atlantic (summer)"}},
+	"NST":  {{hours(-3, 30), "Newfoundland"}},
+	"NDT":  {{hours(-2, 30), "Newfoundland (summer)"}},
+	// South America
+	"BRT": {{hours(-3, 0), "Brasília"}},
+	"ART": {{hours(-3, 0), "Argentina"}},
+	// Europe & Africa
+	"WET":  {{0, "Western Europe"}},
+	"WEST": {{hours(1, 0), "Western Europe (summer)"}},
+	"BST":  {{hours(1, 0), "British (summer)"}, {hours(6, 0), "Bangladesh"}},
+	"IST":  {{hours(5, 30), "India"}, {hours(2, 0), "Israel"}, {hours(1, 0), "Irish (summer)"}},
+	"CET":  {{hours(1, 0), "Central Europe"}},
+	"CEST": {{hours(2, 0), "Central Europe (summer)"}},
+	"EET":  {{hours(2, 0), "Eastern Europe"}},
+	"EEST": {{hours(3, 0), "Eastern Europe (summer)"}},
+	"MSK":  {{hours(3, 0), "Moscow"}},
+	"WAT":  {{hours(1, 0), "West Africa"}},
+	"CAT":  {{hours(2, 0), "Central Africa"}},
+	"SAST": {{hours(2, 0), "South Africa"}},
+	"EAT":  {{hours(3, 0), "East Africa"}},
+	// Asia & Oceania
+	"GST":  {{hours(4, 0), "Gulf"}},
+	"PKT":  {{hours(5, 0), "Pakistan"}},
+	"NPT":  {{hours(5, 45), "Nepal"}},
+	"ICT":  {{hours(7, 0), "Indochina"}},
+	"WIB":  {{hours(7, 0), "Western Indonesia"}},
+	"HKT":  {{hours(8, 0), "Hong Kong"}},
+	"SGT":  {{hours(8, 0), "Singapore"}},
+	"PHT":  {{hours(8, 0), "Philippines"}},
+	"AWST": {{hours(8, 0), "Western Australia"}},
+	"JST":  {{hours(9, 0), "Japan"}},
+	"KST":  {{hours(9, 0), "Korea"}},
+	"ACST": {{hours(9, 30), "Central Australia"}},
+	"ACDT": {{hours(10, 30), "Central Australia (summer)"}},
+	"AEST": {{hours(10, 0), "Eastern Australia"}},
+	"AEDT": {{hours(11, 0), "Eastern Australia (summer)"}},
+	"NZST": {{hours(12, 0), "New Zealand"}},
+	"NZDT": {{hours(13, 0), "New Zealand (summer)"}},
+}
+
+// offsetPattern matches UTC offsets (e.g. +05:30, -8, UTC+2 or GMT-0330).
+var offsetPattern = regexp.MustCompile(`^(?i:UTC|GMT)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+// parseZoneOffset parses a UTC offset and returns a fixed zone for it.
+func parseZoneOffset(zone string) (*time.Location, bool) {
+	m := offsetPattern.FindStringSubmatch(zone)
+	if m == nil {
+		return nil, false
+	}
+	h, _ := strconv.Atoi(m[2])
+	minutes := 0
+	if m[3] != "" {
+		minutes, _ = strconv.Atoi(m[3])
+	}
+	if h > 14 || minutes > 59 {
+		return nil, false
+	}
+	offset := h*3600 + minutes*60
+	if m[1] == "-" {
+		offset = -offset
+	}
+	return time.FixedZone(formatZoneOffset(offset), offset), true
+}
+
+// formatZoneOffset formats the offset as name of a fixed zone (e.g. UTC+05:30).
+func formatZoneOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+// resolveZoneAbbreviation resolves a timezone abbreviation (e.g. PST) to a
+// fixed zone. Ambiguous abbreviations (e.g. IST) use the meaning of a
+// configured location currently using the abbreviation, if any, and the
+// common one otherwise.
+func resolveZoneAbbreviation(config Config, abbreviation string) (*time.Location, bool) {
+	abbreviation = strings.ToUpper(abbreviation)
+	meanings, ok := zoneAbbreviations[abbreviation]
+	if !ok {
+		return nil, false
+	}
+	if len(meanings) > 1 {
+		// Use the first location (local first) using the abbreviation
+		now := time.Now()
+		for _, l := range append([]Location{{TZ: "Local"}}, config.Timezones...) {
+			loc, err := loadLocation(l.TZ)
+			if err != nil {
+				continue
+			}
+			name, offset := now.In(loc).Zone()
+			if name != abbreviation {
+				continue
+			}
+			for _, m := range meanings {
+				if m.Offset == offset {
+					return time.FixedZone(abbreviation, m.Offset), true
+				}
+			}
+		}
+	}
+	return time.FixedZone(abbreviation, meanings[0].Offset), true
+}
+
+// findLocationByName returns the configured location with the given name
+// (case-insensitive), if any.
+func findLocationByName(config Config, name string) (Location, bool) {
+	for _, l := range config.Timezones {
+		if strings.EqualFold(l.Name, name) {
+			return l, true
+		}
+	}
+	return Location{}, false
+}
+
+// locationsByPrefix returns the configured locations whose name starts with the
+// given prefix (case-insensitive).
+func locationsByPrefix(config Config, prefix string) []Location {
+	var matches []Location
+	for _, l := range config.Timezones {
+		if len(l.Name) >= len(prefix) && strings.EqualFold(l.Name[:len(prefix)], prefix) {
+			matches = append(matches, l)
+		}
+	}
+	return matches
+}
+
+// locationNames returns the names of the given locations (comma-separated).
+func locationNames(locations []Location) string {
+	names := make([]string, len(locations))
+	for i, l := range locations {
+		names[i] = l.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// findLocationByPrefix returns the only configured location whose name starts
+// with the given prefix (case-insensitive). It fails, if the prefix is
+// ambiguous.
+func findLocationByPrefix(config Config, prefix string) (Location, bool, error) {
+	matches := locationsByPrefix(config, prefix)
+	switch len(matches) {
+	case 0:
+		return Location{}, false, nil
+	case 1:
+		return matches[0], true, nil
+	default:
+		return Location{}, false, fmt.Errorf("ambiguous location: %s (matches %s)", prefix, locationNames(matches))
+	}
+}
+
+// describeZone describes the zone of the given time (by the name of the
+// configured location using it, if any, and the offset of abbreviations).
+func describeZone(config Config, t time.Time) string {
+	loc := t.Location()
+	if loc == time.Local {
+		return "Local"
+	}
+	for _, l := range config.Timezones {
+		if l.TZ == loc.String() {
+			return l.Name + " (" + l.TZ + ")"
+		}
+	}
+	if _, ok := zoneAbbreviations[loc.String()]; ok {
+		_, offset := t.Zone()
+		return loc.String() + " (" + formatZoneOffset(offset) + ")"
+	}
+	return loc.String()
+}
+
+// resolveRequestZone resolves the timezone of a requested time given as index
+// into the configured timezones (0 is local), name of a configured location,
+// abbreviation (e.g. PST), UTC offset (e.g. +05:30 or UTC-8), IANA identifier
+// or unique prefix of the name of a configured location (in this order).
+func resolveRequestZone(config Config, zone string) (*time.Location, error) {
+	// Handle the timezone index referring to the configured TZs (unsigned only,
+	// signed numbers are offsets)
+	if tzIndex, err := strconv.Atoi(zone); err == nil && strings.Trim(zone, "0123456789") == "" {
+		if tzIndex < 0 || tzIndex > len(config.Timezones) {
+			return nil, fmt.Errorf("timezone-index out of range: %d", tzIndex)
+		}
+		// Get timezone at index (offset by one to account for 0 as local timezone)
+		if tzIndex == 0 {
+			return time.Local, nil
+		}
+		loc, err := loadLocation(config.Timezones[tzIndex-1].TZ)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s (given index %d)", config.Timezones[tzIndex-1].TZ, tzIndex)
+		}
+		return loc, nil
+	}
+	// Handle the names of the configured locations
+	if l, ok := findLocationByName(config, zone); ok {
+		loc, err := loadLocation(l.TZ)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s (given name %s)", l.TZ, zone)
+		}
+		return loc, nil
+	}
+	// Handle abbreviations (unless also the prefix of a location name) and
+	// offsets
+	if loc, ok := resolveZoneAbbreviation(config, zone); ok {
+		if matches := locationsByPrefix(config, zone); len(matches) > 0 {
+			return nil, fmt.Errorf("ambiguous timezone: %s (abbreviation %s or location %s)", zone, loc.String(), locationNames(matches))
+		}
+		return loc, nil
+	}
+	if loc, ok := parseZoneOffset(zone); ok {
+		return loc, nil
+	}
+	// Handle the explicitly given timezone
+	if checkTimezoneLocation(zone) {
+		return loadLocation(zone)
+	}
+	// Handle unique prefixes of the names of the configured locations
+	l, ok, err := findLocationByPrefix(config, zone)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		loc, err := loadLocation(l.TZ)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s (given name %s)", l.TZ, zone)
+		}
+		return loc, nil
+	}
+	return nil, fmt.Errorf("unknown timezone: %s (should be an index, location name, abbreviation like PST, offset like +05:30 or IANA identifier)", zone)
+}
+
+// isZoneSuffix indicates whether the given word is a timezone abbreviation or
+// UTC offset (as given after a time, e.g. "3pm PST").
+func isZoneSuffix(word string) bool {
+	if _, ok := zoneAbbreviations[strings.ToUpper(word)]; ok {
+		return true
+	}
+	_, ok := parseZoneOffset(word)
+	return ok
+}
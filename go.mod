@@ -5,6 +5,7 @@ go 1.23.0
 require (
 	github.com/adrg/xdg v0.4.0
 	github.com/gdamore/tcell/v2 v2.6.0
+	github.com/mattn/go-runewidth v0.0.14
 	github.com/tidwall/jsonc v0.3.2
 	golang.org/x/term v0.5.0
 )
@@ -12,7 +13,6 @@ require (
 require (
 	github.com/gdamore/encoding v1.0.0 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
-	github.com/mattn/go-runewidth v0.0.14 // indirect
 	github.com/rivo/uniseg v0.4.3 // indirect
 	golang.org/x/sys v0.5.0 // indirect
 	golang.org/x/text v0.7.0 // indirect
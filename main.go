@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -44,8 +45,25 @@ func main() {
 			os.Exit(1)
 		}
 	}
-	// Plot time
-	err = core.Plot(config, rt)
+	// Run command (plot time by default)
+	switch flag.Arg(0) {
+	case core.CommandWho:
+		err = core.Who(config, rt)
+	case core.CommandImport:
+		err = core.Import(config, flag.Arg(1))
+		if err != nil {
+			fmt.Println("error importing:", err)
+			os.Exit(1)
+		}
+	case core.CommandServe:
+		err = core.Serve(config)
+		if err != nil {
+			fmt.Println("error serving:", err)
+			os.Exit(1)
+		}
+	default:
+		err = core.Plot(config, rt)
+	}
 	if err != nil {
 		fmt.Println("error plotting time:", err)
 		os.Exit(1)
@@ -0,0 +1,89 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/merschformann/gotz/core"
+)
+
+// calendarEvent returns a calendar with a single event at the given start.
+func calendarEvent(summary, start string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\n" +
+		"SUMMARY:" + summary + "\r\nDTSTART;" + start + "\r\nDURATION:PT1H\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+}
+
+func TestCalendar(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+
+	// Plot the events of a calendar file (modified in between)
+	file := filepath.Join(t.TempDir(), "calendar.ics")
+	config := core.DefaultConfig()
+	config.Calendars = []string{file}
+	plot := func() (string, error) {
+		sb := strings.Builder{}
+		err := core.PlotTime(newTestPlotter(&sb, config, true), config, testTime)
+		return sb.String(), err
+	}
+	write := func(content string, modTime time.Time) {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Check the event
+	write(calendarEvent("Review", "TZID=Europe/Berlin:19850824T180000"), testTime)
+	output, err := plot()
+	if err != nil || !strings.Contains(output, "Review: ") {
+		t.Errorf("expected event, got %v:\n%s", err, output)
+	}
+
+	// Check that modified calendars are read again
+	write(calendarEvent("Retro", "TZID=Europe/Berlin:19850824T180000"), testTime.Add(time.Hour))
+	output, err = plot()
+	if err != nil || !strings.Contains(output, "Retro: ") || strings.Contains(output, "Review: ") {
+		t.Errorf("expected modified event, got %v:\n%s", err, output)
+	}
+
+	// Check that unknown timezones fall back to local time
+	write(calendarEvent("Review", "TZID=Mars/Olympus:19850824T180000"), testTime.Add(2*time.Hour))
+	output, err = plot()
+	if err != nil || !strings.Contains(output, "Review: LOC 18:00 · NY 14:00 · BER 20:00") {
+		t.Errorf("expected event in local time, got %v:\n%s", err, output)
+	}
+
+	// Check that timezones defined by the calendar are used for other TZIDs
+	// (e.g. the Windows names written by Outlook)
+	write(strings.Replace(calendarEvent("Review", `TZID="W. Europe Standard Time":19850824T180000`),
+		"BEGIN:VEVENT", "BEGIN:VTIMEZONE\r\nTZID:W. Europe Standard Time\r\n"+
+			"BEGIN:STANDARD\r\nDTSTART:16010101T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n"+
+			"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10\r\nEND:STANDARD\r\n"+
+			"BEGIN:DAYLIGHT\r\nDTSTART:16010101T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\n"+
+			"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3\r\nEND:DAYLIGHT\r\nEND:VTIMEZONE\r\nBEGIN:VEVENT", 1),
+		testTime.Add(3*time.Hour))
+	output, err = plot()
+	if err != nil || !strings.Contains(output, "Review: LOC 16:00 · NY 12:00 · BER 18:00") {
+		t.Errorf("expected event in calendar timezone, got %v:\n%s", err, output)
+	}
+
+	// Check that recurrences until a date include that day
+	write(strings.Replace(calendarEvent("Standup", "TZID=Europe/Berlin:19850820T180000"),
+		"DURATION:PT1H", "DURATION:PT1H\r\nRRULE:FREQ=DAILY;UNTIL=19850824", 1),
+		testTime.Add(4*time.Hour))
+	output, err = plot()
+	if err != nil || !strings.Contains(output, "Standup: LOC 16:00 · NY 12:00 · BER 18:00") {
+		t.Errorf("expected last occurrence, got %v:\n%s", err, output)
+	}
+}
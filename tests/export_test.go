@@ -0,0 +1,155 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/merschformann/gotz/core"
+)
+
+func TestExport(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time (and time of creation)
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+	stamp := time.Date(1985, 8, 20, 9, 30, 0, 0, time.UTC)
+
+	// Define test cases
+	tests := []struct {
+		name      string
+		time      time.Time
+		export    core.ExportOptions
+		timeRange core.TimeRange
+		expected  string
+	}{
+		{
+			name:   "ICS",
+			time:   testTime,
+			export: core.ExportOptions{Format: core.ExportICS, Duration: 90 * time.Minute, Title: "Review; part 1"},
+			expected: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"PRODID:-//gotz//gotz//EN\r\n" +
+				"BEGIN:VTIMEZONE\r\n" +
+				"TZID:Europe/Berlin\r\n" +
+				"BEGIN:DAYLIGHT\r\n" +
+				"DTSTART:19850331T020000\r\n" +
+				"TZOFFSETFROM:+0100\r\n" +
+				"TZOFFSETTO:+0200\r\n" +
+				"TZNAME:CEST\r\n" +
+				"END:DAYLIGHT\r\n" +
+				"END:VTIMEZONE\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:19850824T140000Z-5a13566c@gotz\r\n" +
+				"DTSTAMP:19850820T093000Z\r\n" +
+				"DTSTART;TZID=Europe/Berlin:19850824T160000\r\n" +
+				"DTEND;TZID=Europe/Berlin:19850824T173000\r\n" +
+				"SUMMARY:Review\\; part 1\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+		},
+		{
+			name:   "ICSLocal",
+			time:   time.Date(1985, 8, 24, 14, 0, 0, 0, time.Local),
+			export: core.ExportOptions{Format: core.ExportICS, Duration: time.Hour},
+			expected: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"PRODID:-//gotz//gotz//EN\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:19850824T140000Z-61c1568d@gotz\r\n" +
+				"DTSTAMP:19850820T093000Z\r\n" +
+				"DTSTART:19850824T140000Z\r\n" +
+				"DTEND:19850824T150000Z\r\n" +
+				"SUMMARY:Meeting\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+		},
+		{
+			name:   "ICSTransition",
+			time:   time.Date(1985, 9, 29, 1, 30, 0, 0, loc),
+			export: core.ExportOptions{Format: core.ExportICS, Duration: 2 * time.Hour},
+			expected: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"PRODID:-//gotz//gotz//EN\r\n" +
+				"BEGIN:VTIMEZONE\r\n" +
+				"TZID:Europe/Berlin\r\n" +
+				"BEGIN:DAYLIGHT\r\n" +
+				"DTSTART:19850331T020000\r\n" +
+				"TZOFFSETFROM:+0100\r\n" +
+				"TZOFFSETTO:+0200\r\n" +
+				"TZNAME:CEST\r\n" +
+				"END:DAYLIGHT\r\n" +
+				"BEGIN:STANDARD\r\n" +
+				"DTSTART:19850929T030000\r\n" +
+				"TZOFFSETFROM:+0200\r\n" +
+				"TZOFFSETTO:+0100\r\n" +
+				"TZNAME:CET\r\n" +
+				"END:STANDARD\r\n" +
+				"END:VTIMEZONE\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:19850928T233000Z-805945e2@gotz\r\n" +
+				"DTSTAMP:19850820T093000Z\r\n" +
+				"DTSTART;TZID=Europe/Berlin:19850929T013000\r\n" +
+				"DTEND;TZID=Europe/Berlin:19850929T023000\r\n" +
+				"SUMMARY:Meeting\r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+		},
+		{
+			name:   "ICSOffset",
+			time:   time.Date(1985, 8, 24, 19, 30, 0, 0, time.FixedZone("UTC+05:30", 19800)),
+			export: core.ExportOptions{Format: core.ExportICS, Duration: time.Hour, Title: strings.Repeat("Quarterly planning ", 4)},
+			expected: "BEGIN:VCALENDAR\r\n" +
+				"VERSION:2.0\r\n" +
+				"PRODID:-//gotz//gotz//EN\r\n" +
+				"BEGIN:VEVENT\r\n" +
+				"UID:19850824T140000Z-5d7b69a6@gotz\r\n" +
+				"DTSTAMP:19850820T093000Z\r\n" +
+				"DTSTART:19850824T140000Z\r\n" +
+				"DTEND:19850824T150000Z\r\n" +
+				"SUMMARY:Quarterly planning Quarterly planning Quarterly planning Quarterly \r\n" +
+				" planning \r\n" +
+				"END:VEVENT\r\n" +
+				"END:VCALENDAR\r\n",
+		},
+		{
+			name:   "Text",
+			time:   testTime,
+			export: core.ExportOptions{Format: core.ExportText, Duration: time.Hour},
+			expected: "Meeting - Sat 24 Aug 1985 16:00 (Europe/Berlin), 1h\n" +
+				"10:00-11:00 New York / 16:00-17:00 Berlin / 22:00-23:00 Shanghai / 00:00-01:00 Sydney (Sun)\n",
+		},
+		{
+			name:      "TextRange",
+			time:      testTime,
+			export:    core.ExportOptions{Format: core.ExportText, Duration: time.Hour},
+			timeRange: core.TimeRange{Start: testTime, End: testTime.Add(210 * time.Minute)},
+			expected: "Meeting - Sat 24 Aug 1985 16:00 (Europe/Berlin), 3h30m\n" +
+				"10:00-13:30 New York / 16:00-19:30 Berlin / 22:00-01:30 Shanghai / 00:00-03:30 Sydney (Sun)\n",
+		},
+		{
+			name:   "TextMoment",
+			time:   testTime,
+			export: core.ExportOptions{Format: core.ExportText, Title: "Call"},
+			expected: "Call - Sat 24 Aug 1985 16:00 (Europe/Berlin)\n" +
+				"10:00 New York / 16:00 Berlin / 22:00 Shanghai / 00:00 Sydney (Sun)\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := core.DefaultConfig()
+			config.Export = test.export
+			config.Range = test.timeRange
+			sb := strings.Builder{}
+			if err := core.WriteExport(&sb, config, test.time, stamp); err != nil {
+				t.Fatalf("error exporting time: %v", err)
+			}
+			if actual := sb.String(); actual != test.expected {
+				t.Errorf("\nExpected:\n%s\nActual:\n%s", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/merschformann/gotz/core"
+)
+
+func TestImport(t *testing.T) {
+	// Define test cases
+	tests := []struct {
+		name     string
+		file     string
+		options  core.ImportOptions
+		expected core.ImportReport
+	}{
+		{
+			name:    "CSV",
+			file:    "team.csv",
+			options: core.ImportOptions{NameColumn: "employee", TZColumn: "3"},
+			expected: core.ImportReport{
+				Added:   []string{"Alice (Europe/Berlin)", "Bob (America/New_York)"},
+				Skipped: []string{"New York (America/New_York)", "Alice (Europe/Berlin)"},
+				Failed: []core.ImportFailure{
+					{Source: "line 4", Reason: "Carol: invalid timezone: Mars/Olympus"},
+					{Source: "line 5", Reason: "missing name"},
+				},
+			},
+		},
+		{
+			name: "VCard",
+			file: "team.vcf",
+			expected: core.ImportReport{
+				Added: []string{"Dana Smith (Etc/GMT+5)", "Eve (Asia/Kolkata)"},
+				Failed: []core.ImportFailure{
+					{Source: "card 3", Reason: "Frank: no timezone for offset +05:30 (use an IANA name)"},
+					{Source: "card 4", Reason: "Gus: missing timezone"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := core.DefaultConfig()
+			config.Import = test.options
+			existing := len(config.Timezones)
+			updated, report, err := core.ImportLocations(config, filepath.Join("testdata", "import", test.file))
+			if err != nil {
+				t.Fatalf("error importing: %v", err)
+			}
+			if !reflect.DeepEqual(report, test.expected) {
+				t.Errorf("\nExpected: %+v\nActual:   %+v", test.expected, report)
+			}
+			// Check that the new locations were appended (without touching the
+			// original configuration)
+			if len(updated.Timezones) != existing+len(test.expected.Added) {
+				t.Errorf("expected %d timezones, got %d", existing+len(test.expected.Added), len(updated.Timezones))
+			}
+			if len(config.Timezones) != existing {
+				t.Errorf("original configuration was modified")
+			}
+		})
+	}
+
+	// Check import of people into the roster
+	roster := core.Roster{People: []core.Person{{Name: "eve", TZ: "Asia/Kolkata"}}}
+	roster, report, err := core.ImportPeople(roster, core.DefaultConfig(), filepath.Join("testdata", "import", "team.vcf"))
+	if err != nil {
+		t.Fatalf("error importing: %v", err)
+	}
+	if len(roster.People) != 2 || roster.People[1].Name != "Dana Smith" || len(report.Skipped) != 1 {
+		t.Errorf("unexpected roster after import: %+v (report %+v)", roster, report)
+	}
+}
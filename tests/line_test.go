@@ -0,0 +1,115 @@
+package core_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/merschformann/gotz/core"
+)
+
+func TestLine(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+
+	// Define test cases
+	tests := []struct {
+		name     string
+		line     core.LineFormat
+		expected string
+	}{
+		{
+			name:     "Default",
+			line:     core.LineFormat{},
+			expected: "NY 10:00 · BER 16:00 · SHA 22:00 · SYD 00:00\n",
+		},
+		{
+			name:     "Template",
+			line:     core.LineFormat{Template: "{name} {time} ({zone})", Separator: " | "},
+			expected: "New York 10:00 (EDT) | Berlin 16:00 (CEST) | Shanghai 22:00 (CST) | Sydney 00:00 (AEST)\n",
+		},
+		{
+			name:     "Emoji",
+			line:     core.LineFormat{Indicators: core.IndicatorsEmoji, Local: true},
+			expected: "☀️ LOC 14:00 · ☀️ NY 10:00 · ☀️ BER 16:00 · 🌙 SHA 22:00 · 🌙 SYD 00:00\n",
+		},
+		{
+			name:     "Color",
+			line:     core.LineFormat{Template: "{abbr}", Indicators: core.IndicatorsColor},
+			expected: "[day]NY · [day]BER · [night]SHA · [night]SYD\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Prepare configuration
+			config := core.DefaultConfig()
+			config.Output = core.OutputLine
+			config.Line = test.line
+			// Setup plotter (collect output, marking all non-normal contexts)
+			sb := strings.Builder{}
+			plotter := core.Plotter{
+				PlotLine: func(t core.ContextType, line ...interface{}) {
+					sb.WriteString(fmt.Sprint(line...) + "\n")
+				},
+				PlotString: func(t core.ContextType, msg string) {
+					if t != core.ContextNormal {
+						msg = "[" + string(t) + "]" + msg
+					}
+					sb.WriteString(msg)
+				},
+				Symbols: core.GetSymbols(config.Style),
+			}
+			// Print line
+			if err := core.PlotTime(plotter, config, testTime); err != nil {
+				t.Fatalf("error plotting time: %s", err)
+			}
+			if actual := sb.String(); actual != test.expected {
+				t.Errorf("\nExpected: %sActual:   %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLineColorIndicators(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+
+	// Colored indicators color the line, even if colors are disabled otherwise
+	config := core.DefaultConfig()
+	config.Style.Colorize = false
+	config.Output = core.OutputLine
+	config.Line = core.LineFormat{Template: "{abbr}", Indicators: core.IndicatorsColor}
+
+	// Capture the output written to stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	err = core.Plot(config, testTime)
+	os.Stdout = stdout
+	w.Close()
+	if err != nil {
+		t.Fatalf("error plotting time: %s", err)
+	}
+	output, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "NY"+core.ColorReset) {
+		t.Errorf("expected colored locations, got %q", output)
+	}
+}
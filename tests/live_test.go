@@ -0,0 +1,234 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/merschformann/gotz/core"
+)
+
+// frameScreen wraps a simulation screen and signals every frame shown.
+type frameScreen struct {
+	tcell.SimulationScreen
+	frames chan struct{}
+}
+
+func (s *frameScreen) Show() {
+	s.SimulationScreen.Show()
+	s.frames <- struct{}{}
+}
+
+func (s *frameScreen) Sync() {
+	s.SimulationScreen.Sync()
+	s.frames <- struct{}{}
+}
+
+// awaitFrame waits for the next frame to be shown.
+func awaitFrame(t *testing.T, s *frameScreen) {
+	t.Helper()
+	select {
+	case <-s.frames:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for frame")
+	}
+}
+
+// colorLetters are the letters used to represent distinct colors.
+const colorLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// renderScreen converts the current screen contents to a string. If styles is
+// true, the foreground colors are appended as a grid of letters (one letter per
+// distinct color) followed by a legend.
+func renderScreen(s tcell.SimulationScreen, styles bool) string {
+	cells, width, height := s.GetContents()
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("size %dx%d\n", width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if r := cells[y*width+x].Runes; len(r) > 0 {
+				sb.WriteRune(r[0])
+			} else {
+				sb.WriteRune(' ')
+			}
+		}
+		sb.WriteString("\n")
+	}
+	if !styles {
+		return sb.String()
+	}
+	letters := map[tcell.Color]rune{}
+	legend := []string{}
+	sb.WriteString("colors\n")
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fg, _, _ := cells[y*width+x].Style.Decompose()
+			if fg == tcell.ColorDefault {
+				sb.WriteRune('.')
+				continue
+			}
+			letter, ok := letters[fg]
+			if !ok {
+				letter = rune(colorLetters[len(letters)%len(colorLetters)])
+				letters[fg] = letter
+				legend = append(legend, fmt.Sprintf("%c: #%06x", letter, fg.Hex()))
+			}
+			sb.WriteRune(letter)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(strings.Join(legend, "\n") + "\n")
+	return sb.String()
+}
+
+func TestLive(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+
+	// Define test cases
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		// Size to resize to after the first frame (none, if zero)
+		resize [2]int
+		// Duration to advance the clock by after the first frame (none, if
+		// zero)
+		advance time.Duration
+		// Key used to quit
+		quit   tcell.Key
+		styles bool
+	}{
+		{
+			name:   "default",
+			width:  72,
+			height: 14,
+			resize: [2]int{48, 12},
+			quit:   tcell.KeyRune,
+		},
+		{
+			name:    "seconds",
+			width:   72,
+			height:  8,
+			advance: time.Second,
+			quit:    tcell.KeyCtrlC,
+		},
+		{
+			name:   "colorize",
+			width:  72,
+			height: 16,
+			quit:   tcell.KeyEscape,
+			styles: true,
+		},
+		{
+			name:   "gradient",
+			width:  72,
+			height: 8,
+			quit:   tcell.KeyEscape,
+			styles: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := readConfig(t, filepath.Join("testdata", "live", test.name+".json"))
+
+			// Prepare adjustable clock
+			var mu sync.Mutex
+			now := testTime
+			clock := func() time.Time {
+				mu.Lock()
+				defer mu.Unlock()
+				return now
+			}
+
+			// Prepare simulation screen
+			sim := tcell.NewSimulationScreen("")
+			if err := sim.Init(); err != nil {
+				t.Fatal(err)
+			}
+			defer sim.Fini()
+			sim.SetSize(test.width, test.height)
+			s := &frameScreen{SimulationScreen: sim, frames: make(chan struct{})}
+
+			// Run live mode in the background
+			done := make(chan error, 1)
+			go func() {
+				done <- core.PlotLive(context.Background(), s, config, clock)
+			}()
+
+			// Collect frames
+			awaitFrame(t, s)
+			actual := renderScreen(sim, test.styles)
+			if test.resize != [2]int{} {
+				sim.SetSize(test.resize[0], test.resize[1])
+				if err := sim.PostEvent(tcell.NewEventResize(test.resize[0], test.resize[1])); err != nil {
+					t.Fatal(err)
+				}
+				awaitFrame(t, s)
+				actual += renderScreen(sim, test.styles)
+			}
+			if test.advance != 0 {
+				mu.Lock()
+				now = now.Add(test.advance)
+				mu.Unlock()
+				if err := sim.PostEvent(tcell.NewEventInterrupt(nil)); err != nil {
+					t.Fatal(err)
+				}
+				awaitFrame(t, s)
+				actual += renderScreen(sim, test.styles)
+			}
+
+			// Quit
+			sim.InjectKey(test.quit, 'q', tcell.ModNone)
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("error in live mode: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for live mode to quit")
+			}
+
+			// Update or compare golden file
+			compareGolden(t, filepath.Join("testdata", "live", test.name+".golden"), actual)
+		})
+	}
+}
+
+func TestLiveCancel(t *testing.T) {
+	// Prepare simulation screen
+	sim := tcell.NewSimulationScreen("")
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Fini()
+	sim.SetSize(72, 14)
+	s := &frameScreen{SimulationScreen: sim, frames: make(chan struct{}, 100)}
+
+	// Run live mode until the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- core.PlotLive(ctx, s, core.DefaultConfig(), time.Now)
+	}()
+	awaitFrame(t, s)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("error in live mode: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for live mode to stop")
+	}
+}
@@ -0,0 +1,31 @@
+package core_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/merschformann/gotz/core"
+)
+
+func TestMarks(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test times (the first one is the requested one)
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	marks := []core.Mark{
+		{Time: time.Date(1985, 8, 24, 16, 0, 0, 0, berlin)},
+		{Label: "retro", Time: time.Date(1985, 8, 24, 21, 0, 0, 0, berlin)},
+		{Label: "sync", Time: time.Date(1985, 8, 25, 9, 0, 0, 0, shanghai)},
+	}
+
+	for _, name := range []string{"default", "inline", "vertical"} {
+		t.Run(name, func(t *testing.T) {
+			config := readConfig(t, filepath.Join("testdata", "static_"+name+".json"))
+			config.Marks = marks
+			plotGolden(t, filepath.Join("testdata", "marks", name+".golden"), config, false, marks[0].Time)
+		})
+	}
+}
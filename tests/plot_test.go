@@ -27,6 +27,66 @@ func readExpectation(goldenFile string) (string, error) {
 	return string(expected), nil
 }
 
+// compareGolden compares the actual output with the golden file (or updates
+// the golden file, if desired).
+func compareGolden(t *testing.T, goldenFile, actual string) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(goldenFile, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := readExpectation(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != expected {
+		t.Errorf("\nExpected:\n%s\nActual:\n%s", expected, actual)
+	}
+}
+
+// readConfig reads a test configuration file.
+func readConfig(t *testing.T, configFile string) core.Config {
+	t.Helper()
+	var config core.Config
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+// newTestPlotter creates a plotter collecting the output in the given builder
+// (at the default test width of 72).
+func newTestPlotter(sb *strings.Builder, config core.Config, now bool) core.Plotter {
+	return core.Plotter{
+		Now:           now,
+		TerminalWidth: 72,
+		PlotLine: func(t core.ContextType, line ...interface{}) {
+			sb.WriteString(fmt.Sprint(line...) + "\n")
+		},
+		PlotString: func(t core.ContextType, msg string) {
+			sb.WriteString(msg)
+		},
+		Symbols: core.GetSymbols(config.Style),
+	}
+}
+
+// plotGolden plots the time and compares the output with the golden file.
+func plotGolden(t *testing.T, goldenFile string, config core.Config, now bool, tm time.Time) {
+	t.Helper()
+	sb := strings.Builder{}
+	if err := core.PlotTime(newTestPlotter(&sb, config, now), config, tm); err != nil {
+		t.Fatalf("error plotting time: %s", err)
+	}
+	compareGolden(t, goldenFile, sb.String())
+}
+
 func TestTableStatic(t *testing.T) {
 	// Get all test configurations
 	testConfigurations, err := filepath.Glob("testdata/*.json")
@@ -97,3 +157,35 @@ func TestTableStatic(t *testing.T) {
 		})
 	}
 }
+
+func TestVerticalNarrow(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+
+	// Plot in a terminal too narrow for any column (only the axis remains)
+	config := core.DefaultConfig()
+	config.Layout = core.LayoutVertical
+	sb := strings.Builder{}
+	plotter := core.Plotter{
+		Now:           true,
+		TerminalWidth: 10,
+		PlotLine: func(t core.ContextType, line ...interface{}) {
+			sb.WriteString(fmt.Sprint(line...) + "\n")
+		},
+		PlotString: func(t core.ContextType, msg string) {
+			sb.WriteString(msg)
+		},
+		Symbols: core.GetSymbols(config.Style),
+	}
+	if err := core.PlotTime(plotter, config, testTime); err != nil {
+		t.Fatalf("error plotting time: %s", err)
+	}
+	lines := strings.Split(sb.String(), "\n")
+	if len(lines) < 16 || lines[14] != "now   " || lines[15] != "16:00" {
+		t.Errorf("unexpected narrow plot:\n%s", sb.String())
+	}
+}
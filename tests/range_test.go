@@ -0,0 +1,29 @@
+package core_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/merschformann/gotz/core"
+)
+
+func TestRange(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test range (a late meeting in Berlin)
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	timeRange := core.TimeRange{
+		Start: time.Date(1985, 8, 24, 16, 0, 0, 0, loc),
+		End:   time.Date(1985, 8, 24, 19, 30, 0, 0, loc),
+	}
+
+	for _, name := range []string{"default", "inline", "vertical"} {
+		t.Run(name, func(t *testing.T) {
+			config := readConfig(t, filepath.Join("testdata", "static_"+name+".json"))
+			config.Range = timeRange
+			plotGolden(t, filepath.Join("testdata", "range", name+".golden"), config, false, timeRange.Start)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package core_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -53,3 +54,176 @@ func TestParseRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRequestRange(t *testing.T) {
+	defaultConfig := core.DefaultConfig()
+	berlinTZ, _ := time.LoadLocation("Europe/Berlin")
+	// Define test cases (durations of zero refer to single times)
+	tests := []struct {
+		name     string
+		input    string
+		start    string
+		duration time.Duration
+		err      bool
+	}{
+		{name: "Single", input: "15:00@2", start: "15:00"},
+		{name: "Range", input: "15:00-16:30@2", start: "15:00", duration: 90 * time.Minute},
+		{name: "Midnight", input: "10pm-1am@2", start: "22:00", duration: 3 * time.Hour},
+		{name: "Dates", input: "1985-08-24T09:00:00-1985-08-25T10:00:00@2", start: "09:00", duration: 25 * time.Hour},
+		{name: "DateAndTime", input: "1985-08-24T22:00:00-1am@2", start: "22:00", duration: 3 * time.Hour},
+		{name: "Offset", input: "1985-08-24T09:00:00-08:00@2", start: "19:00"}, // RFC 3339, not a range
+		{name: "Reversed", input: "1985-08-24T09:00:00-1985-08-23T10:00:00@2", err: true},
+		{name: "Invalid", input: "15:00-xx@2", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Parse the request
+			r, err := core.ParseRequestRange(defaultConfig, test.input)
+			if test.err {
+				if err == nil {
+					t.Fatalf("Expected error, got %v", r)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error parsing request: %v", err)
+			}
+			if r.Start.In(berlinTZ).Format("15:04") != test.start {
+				t.Errorf("Expected start %s in %v, got %v", test.start, berlinTZ, r.Start)
+			}
+			if test.duration == 0 && !r.IsZero() {
+				t.Errorf("Expected single time, got range %v", r)
+			}
+			if test.duration > 0 && r.End.Sub(r.Start) != test.duration {
+				t.Errorf("Expected duration %v, got %v", test.duration, r.End.Sub(r.Start))
+			}
+		})
+	}
+}
+
+func TestParseRequestFormats(t *testing.T) {
+	defaultConfig := core.DefaultConfig()
+	berlinTZ, _ := time.LoadLocation("Europe/Berlin")
+	year := time.Now().In(berlinTZ).Year()
+	// Define test cases (times without zone refer to Berlin)
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+	}{
+		{name: "UnixSeconds", input: "1697040000", expected: time.Date(2023, 10, 11, 16, 0, 0, 0, time.UTC)},
+		{name: "UnixMilliseconds", input: "1697040000123", expected: time.Date(2023, 10, 11, 16, 0, 0, 123e6, time.UTC)},
+		{name: "RFC2822", input: "Mon, 02 Jan 2006 15:04:05 -0700", expected: time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)},
+		{name: "RFC2822Short", input: "2 Jan 2006 15:04:05 +0100", expected: time.Date(2006, 1, 2, 14, 4, 5, 0, time.UTC)},
+		{name: "LogUTC", input: "2023-10-11 14:00:00 UTC", expected: time.Date(2023, 10, 11, 14, 0, 0, 0, time.UTC)},
+		{name: "LogOffset", input: "2023-10-11 14:00:00 -0700", expected: time.Date(2023, 10, 11, 21, 0, 0, 0, time.UTC)},
+		{name: "RFC3339Fraction", input: "2023-10-11T14:00:00.5Z", expected: time.Date(2023, 10, 11, 14, 0, 0, 5e8, time.UTC)},
+		{name: "DateTime", input: "2023-10-11 14:00", expected: time.Date(2023, 10, 11, 14, 0, 0, 0, berlinTZ)},
+		{name: "DateTimeT", input: "2023-10-11T14:00", expected: time.Date(2023, 10, 11, 14, 0, 0, 0, berlinTZ)},
+		{name: "Date", input: "2023-10-11", expected: time.Date(2023, 10, 11, 0, 0, 0, 0, berlinTZ)},
+		{name: "MonthDay", input: "10/11 3pm", expected: time.Date(year, 10, 11, 15, 0, 0, 0, berlinTZ)},
+		{name: "MonthDayYear", input: "10/11/2023 3:30pm", expected: time.Date(2023, 10, 11, 15, 30, 0, 0, berlinTZ)},
+		{name: "DayMonth", input: "11.10. 15:00", expected: time.Date(year, 10, 11, 15, 0, 0, 0, berlinTZ)},
+		{name: "DayMonthYear", input: "11.10.2023", expected: time.Date(2023, 10, 11, 0, 0, 0, 0, berlinTZ)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsedTime, err := core.ParseRequestTime(defaultConfig, test.input+"@Europe/Berlin")
+			if err != nil {
+				t.Fatalf("Error parsing request: %v", err)
+			}
+			if !parsedTime.Equal(test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, parsedTime)
+			}
+		})
+	}
+
+	// Check that errors list the accepted formats
+	_, err := core.ParseRequestTime(defaultConfig, "noon")
+	if err == nil || !strings.Contains(err.Error(), "accepted: 15, 15:04") {
+		t.Errorf("Expected error listing the accepted formats, got %v", err)
+	}
+}
+
+func TestParseRequestZones(t *testing.T) {
+	defaultConfig := core.DefaultConfig()
+	// Use locations without ambiguous abbreviations (but with an office)
+	officeConfig := core.DefaultConfig()
+	officeConfig.Timezones = []core.Location{
+		{Name: "Office", TZ: "America/New_York"},
+		{Name: "Home", TZ: "Europe/Berlin"},
+	}
+	// Define test cases (all at 15:00 of the given zone)
+	tests := []struct {
+		name   string
+		config core.Config
+		input  string
+		offset time.Duration
+	}{
+		{name: "Offset", config: defaultConfig, input: "@+05:30", offset: 5*time.Hour + 30*time.Minute},
+		{name: "OffsetUTC", config: defaultConfig, input: "@UTC-8", offset: -8 * time.Hour},
+		{name: "OffsetGMT", config: defaultConfig, input: "@GMT+0930", offset: 9*time.Hour + 30*time.Minute},
+		{name: "OffsetHours", config: defaultConfig, input: "@+5", offset: 5 * time.Hour},
+		{name: "OffsetHoursNegative", config: defaultConfig, input: "@-8", offset: -8 * time.Hour},
+		{name: "OffsetHoursSpace", config: defaultConfig, input: " +3", offset: 3 * time.Hour},
+		{name: "OffsetHoursSpaceNegative", config: defaultConfig, input: " -8", offset: -8 * time.Hour},
+		{name: "Abbreviation", config: defaultConfig, input: "@PST", offset: -8 * time.Hour},
+		{name: "AbbreviationLower", config: defaultConfig, input: "@jst", offset: 9 * time.Hour},
+		{name: "AbbreviationSpace", config: defaultConfig, input: " PST", offset: -8 * time.Hour},
+		{name: "OffsetSpace", config: defaultConfig, input: " UTC+2", offset: 2 * time.Hour},
+		{name: "AmbiguousDefault", config: defaultConfig, input: " IST", offset: 5*time.Hour + 30*time.Minute},
+		{name: "AmbiguousConfigured", config: defaultConfig, input: "@CST", offset: 8 * time.Hour}, // Shanghai
+		{name: "AmbiguousCommon", config: officeConfig, input: "@CST", offset: -6 * time.Hour},
+		{name: "Name", config: officeConfig, input: "@office", offset: -4 * time.Hour},
+		{name: "NamePrefix", config: officeConfig, input: "@off", offset: -4 * time.Hour},
+		{name: "NamePrefixUpper", config: officeConfig, input: "@HO", offset: 2 * time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsedTime, err := core.ParseRequestTime(test.config, "2023-07-11T15:00:00"+test.input)
+			if err != nil {
+				t.Fatalf("Error parsing request: %v", err)
+			}
+			expected := time.Date(2023, 7, 11, 15, 0, 0, 0, time.UTC).Add(-test.offset)
+			if !parsedTime.Equal(expected) {
+				t.Errorf("Expected %v, got %v", expected.UTC(), parsedTime.UTC())
+			}
+		})
+	}
+
+	// Check that signed offsets are no indices (for short times too)
+	for input, offset := range map[string]time.Duration{"15@+5": 5 * time.Hour, "15 +3": 3 * time.Hour, "15 -8": -8 * time.Hour} {
+		parsedTime, err := core.ParseRequestTime(defaultConfig, input)
+		if err != nil {
+			t.Errorf("Error parsing request %s: %v", input, err)
+			continue
+		}
+		if _, actual := parsedTime.Zone(); time.Duration(actual)*time.Second != offset || parsedTime.Hour() != 15 {
+			t.Errorf("Expected 15:00 at offset %v for %s, got %v", offset, input, parsedTime)
+		}
+	}
+
+	// Check unknown zones and ambiguous prefixes
+	if _, err := core.ParseRequestTime(defaultConfig, "15@Mars"); err == nil {
+		t.Errorf("Expected error for unknown zone")
+	}
+	istanbulConfig := core.DefaultConfig()
+	istanbulConfig.Timezones = []core.Location{{Name: "Istanbul", TZ: "Europe/Istanbul"}}
+	for _, input := range []string{"15@ist", "15 IST"} {
+		_, err := core.ParseRequestTime(istanbulConfig, input)
+		if err == nil || !strings.Contains(err.Error(), "abbreviation IST or location Istanbul") {
+			t.Errorf("Expected error for abbreviation matching a location prefix (%s), got %v", input, err)
+		}
+	}
+	if _, err := core.ParseRequestTime(istanbulConfig, "15@istanbul"); err != nil {
+		t.Errorf("Error parsing request with location name: %v", err)
+	}
+	officeConfig.Timezones = append(officeConfig.Timezones, core.Location{Name: "Old office", TZ: "Europe/London"})
+	_, err := core.ParseRequestTime(officeConfig, "15@o")
+	if err == nil || !strings.Contains(err.Error(), "matches Office, Old office") {
+		t.Errorf("Expected error for ambiguous prefix, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/merschformann/gotz/core"
+)
+
+func TestWho(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test times
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	saturday := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+	tuesday := time.Date(1985, 8, 27, 3, 0, 0, 0, loc)
+
+	// Define test cases
+	tests := []struct {
+		name     string
+		roster   string
+		time     time.Time
+		tags     []string
+		expected string
+	}{
+		{
+			name:   "Weekend",
+			roster: "roster.json",
+			time:   saturday,
+			expected: "[day]working (1)\n" +
+				"  Bob   America/New_York Sat 10:00 frontend\n" +
+				"[evening]available (4)\n" +
+				"  Alice Europe/Berlin    Sat 16:00 backend\n" +
+				"  Dana  Europe/Berlin    Sat 16:00\n" +
+				"  Emil  Europe/Berlin    Sat 16:00 ops\n" +
+				"  Femi  Europe/Berlin    Sat 16:00\n" +
+				"[night]asleep (1)\n" +
+				"  Chen  Asia/Shanghai    Sat 22:00 backend\n",
+		},
+		{
+			name:   "Night",
+			roster: "roster.json",
+			time:   tuesday,
+			expected: "[day]working (2)\n" +
+				"  Chen  Asia/Shanghai    Tue 09:00 backend\n" +
+				"  Emil  Europe/Berlin    Tue 03:00 ops\n" +
+				"[evening]available (1)\n" +
+				"  Bob   America/New_York Mon 21:00 frontend\n" +
+				"[night]asleep (3)\n" +
+				"  Alice Europe/Berlin    Tue 03:00 backend\n" +
+				"  Dana  Europe/Berlin    Tue 03:00\n" +
+				"  Femi  Europe/Berlin    Tue 03:00\n",
+		},
+		{
+			name:   "Tags",
+			roster: "roster.json",
+			time:   tuesday,
+			tags:   []string{"Backend", "ops"},
+			expected: "[day]working (2)\n" +
+				"  Chen  Asia/Shanghai Tue 09:00 backend\n" +
+				"  Emil  Europe/Berlin Tue 03:00 ops\n" +
+				"[evening]available (0)\n" +
+				"[night]asleep (1)\n" +
+				"  Alice Europe/Berlin Tue 03:00 backend\n",
+		},
+		{
+			name:   "CSV",
+			roster: "roster.csv",
+			time:   saturday,
+			expected: "[day]working (1)\n" +
+				"  Bob   America/New_York Sat 10:00 frontend\n" +
+				"[evening]available (1)\n" +
+				"  Alice Europe/Berlin    Sat 16:00 backend\n" +
+				"[night]asleep (1)\n" +
+				"  Chen  Asia/Shanghai    Sat 22:00 backend infra\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := core.DefaultConfig()
+			config.Roster = filepath.Join("testdata", "roster", test.roster)
+			config.RosterTags = test.tags
+			// Setup plotter (collect output, marking all non-normal contexts)
+			sb := strings.Builder{}
+			plotter := core.Plotter{
+				PlotLine: func(t core.ContextType, line ...interface{}) {
+					if t != core.ContextNormal {
+						sb.WriteString("[" + string(t) + "]")
+					}
+					sb.WriteString(fmt.Sprint(line...) + "\n")
+				},
+				PlotString: func(t core.ContextType, msg string) {
+					sb.WriteString(msg)
+				},
+			}
+			if err := core.PlotWho(plotter, config, test.time); err != nil {
+				t.Fatalf("error listing people: %v", err)
+			}
+			if actual := sb.String(); actual != test.expected {
+				t.Errorf("\nExpected:\n%s\nActual:\n%s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTeamLive(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Copy the roster (removed after the first frame)
+	data, err := os.ReadFile(filepath.Join("testdata", "roster", "roster.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "roster.json")
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := core.DefaultConfig()
+	config.View = core.ViewTeam
+	config.Roster = file
+
+	// Prepare simulation screen
+	sim := tcell.NewSimulationScreen("")
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Fini()
+	sim.SetSize(72, 14)
+	s := &frameScreen{SimulationScreen: sim, frames: make(chan struct{}, 100)}
+
+	// Run live mode (the roster is only loaded once, hence, redrawing
+	// succeeds without it)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- core.PlotLive(ctx, s, config, time.Now)
+	}()
+	awaitFrame(t, s)
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.PostEvent(tcell.NewEventResize(60, 14)); err != nil {
+		t.Fatal(err)
+	}
+	awaitFrame(t, s)
+	if frame := renderScreen(sim, false); !strings.Contains(frame, "Alice") {
+		t.Errorf("expected team in frame:\n%s", frame)
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("error in live mode: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for live mode to stop")
+	}
+}
@@ -0,0 +1,177 @@
+package core_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/merschformann/gotz/core"
+)
+
+func TestServe(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time (serving as current one)
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+
+	config := core.DefaultConfig()
+	config.Timezones = []core.Location{
+		{Name: "Berlin", TZ: "Europe/Berlin"},
+		{Name: "New York", TZ: "America/New_York"},
+	}
+	handler, err := core.NewServeHandler(config, func() time.Time { return testTime })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Define test cases
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		expected string
+	}{
+		{
+			name:   "Now",
+			path:   "/api/now",
+			status: http.StatusOK,
+			expected: `{"time":"1985-08-24T16:00:00+02:00","locations":[` +
+				`{"name":"Local","tz":"UTC","time":"1985-08-24T14:00:00Z","display":"14:00","segment":"day"},` +
+				`{"name":"Berlin","tz":"Europe/Berlin","time":"1985-08-24T16:00:00+02:00","display":"16:00","segment":"day"},` +
+				`{"name":"New York","tz":"America/New_York","time":"1985-08-24T10:00:00-04:00","display":"10:00","segment":"day"}]}`,
+		},
+		{
+			name:   "At",
+			path:   "/api/at?time=1985-08-24T21:30:00@2",
+			status: http.StatusOK,
+			expected: `{"time":"1985-08-24T21:30:00-04:00","locations":[` +
+				`{"name":"Local","tz":"UTC","time":"1985-08-25T01:30:00Z","display":"01:30","segment":"night"},` +
+				`{"name":"Berlin","tz":"Europe/Berlin","time":"1985-08-25T03:30:00+02:00","display":"03:30","segment":"night"},` +
+				`{"name":"New York","tz":"America/New_York","time":"1985-08-24T21:30:00-04:00","display":"21:30","segment":"evening"}]}`,
+		},
+		{
+			name:   "AtInvalid",
+			path:   "/api/at?time=noon",
+			status: http.StatusBadRequest,
+			expected: `{"error":"invalid time: noon (accepted: 15, 15:04, 15:04:05, 3pm, 3:04pm, 1504, 150405, ` +
+				`2006-01-02, 2006-01-02 15:04[:05] (also with T, Z07:00, -0700 or UTC), 1/2[/2006] [3pm] (month/day), ` +
+				`2.1.[2006] [15:04] (day.month.), Mon, 2 Jan 2006 15:04:05 -0700, ` +
+				`Unix time in seconds (9+ digits) or milliseconds (12+ digits))"}`,
+		},
+		{
+			name:     "AtMissing",
+			path:     "/api/at",
+			status:   http.StatusBadRequest,
+			expected: `{"error":"missing time (e.g. /api/at?time=15@Asia/Tokyo)"}`,
+		},
+		{
+			name:   "Meet",
+			path:   "/api/meet?duration=90m",
+			status: http.StatusOK,
+			expected: `[{"start":"1985-08-24T16:00:00+02:00","end":"1985-08-24T18:00:00+02:00","locations":[` +
+				`{"name":"Local","tz":"UTC","time":"1985-08-24T14:00:00Z","display":"14:00","segment":"day"},` +
+				`{"name":"Berlin","tz":"Europe/Berlin","time":"1985-08-24T16:00:00+02:00","display":"16:00","segment":"day"},` +
+				`{"name":"New York","tz":"America/New_York","time":"1985-08-24T10:00:00-04:00","display":"10:00","segment":"day"}]},` +
+				`{"start":"1985-08-25T14:00:00+02:00","end":"1985-08-25T16:00:00+02:00","locations":[` +
+				`{"name":"Local","tz":"UTC","time":"1985-08-25T12:00:00Z","display":"12:00","segment":"day"},` +
+				`{"name":"Berlin","tz":"Europe/Berlin","time":"1985-08-25T14:00:00+02:00","display":"14:00","segment":"day"},` +
+				`{"name":"New York","tz":"America/New_York","time":"1985-08-25T08:00:00-04:00","display":"08:00","segment":"day"}]}]`,
+		},
+		{
+			name:     "MeetNone",
+			path:     "/api/meet?duration=5h",
+			status:   http.StatusOK,
+			expected: `[]`,
+		},
+		{
+			name:     "MeetInvalid",
+			path:     "/api/meet?duration=long",
+			status:   http.StatusBadRequest,
+			expected: `{"error":"invalid duration: long"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.path, nil))
+			if recorder.Code != test.status {
+				t.Errorf("expected status %d, got %d", test.status, recorder.Code)
+			}
+			if actual := strings.TrimSpace(recorder.Body.String()); actual != test.expected {
+				t.Errorf("\nExpected:\n%s\nActual:\n%s", test.expected, actual)
+			}
+		})
+	}
+
+	// Check the page (refreshing every minute and showing the plot)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?width=40", nil))
+	page := recorder.Body.String()
+	for _, expected := range []string{
+		`<meta http-equiv="refresh" content="60">`,
+		"Berlin  : Sat 24 Aug 1985 16:00 ",
+		`<span style="color:yellow">`,
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("page does not contain %q:\n%s", expected, page)
+		}
+	}
+
+	// Check that huge widths are limited
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?width=100000000", nil))
+	for _, line := range strings.Split(recorder.Body.String(), "\n") {
+		if len(line) > 10000 {
+			t.Errorf("page line too long (%d bytes)", len(line))
+		}
+	}
+}
+
+func TestServeTeam(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time (serving as current one)
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+
+	// Copy the roster (removed after creating the handler)
+	data, err := os.ReadFile(filepath.Join("testdata", "roster", "roster.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "roster.json")
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := core.DefaultConfig()
+	config.View = core.ViewTeam
+	config.Roster = file
+	handler, err := core.NewServeHandler(config, func() time.Time { return testTime })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check that the API and the page both use the zones of the team (loaded
+	// once)
+	for path, expected := range map[string]string{
+		"/api/now": `{"name":"Chen","tz":"Asia/Shanghai"`,
+		"/":        "Chen                : Sat 24 Aug 1985 22:00 ",
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), expected) {
+			t.Errorf("expected %q at %s, got status %d:\n%s", expected, path, recorder.Code, recorder.Body.String())
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/merschformann/gotz/core"
+)
+
+// cancelWriter collects the output and cancels the context on the first write
+// (i.e., after the first update).
+type cancelWriter struct {
+	bytes.Buffer
+	cancel context.CancelFunc
+}
+
+func (w *cancelWriter) Write(p []byte) (int, error) {
+	w.cancel()
+	return w.Buffer.Write(p)
+}
+
+func TestStatusBar(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+
+	for _, bar := range []string{core.StatusBarWaybar, core.StatusBarI3bar, core.StatusBarPolybar} {
+		t.Run(bar, func(t *testing.T) {
+			config := readConfig(t, filepath.Join("testdata", "static_default.json"))
+			config.StatusBar = bar
+
+			// Stream the first update only
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			w := &cancelWriter{cancel: cancel}
+			clock := func() time.Time { return testTime }
+			if err := core.StreamStatusBar(ctx, w, config, clock); err != nil {
+				t.Fatalf("error streaming status bar: %v", err)
+			}
+
+			// Update or compare golden file
+			compareGolden(t, filepath.Join("testdata", "statusbar", bar+".golden"), w.String())
+		})
+	}
+}
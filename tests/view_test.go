@@ -0,0 +1,27 @@
+package core_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/merschformann/gotz/core"
+)
+
+func TestView(t *testing.T) {
+	// Set local time to UTC for reproducibility
+	time.Local = time.UTC
+
+	// Specify test time
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	testTime := time.Date(1985, 8, 24, 16, 0, 0, 0, loc)
+
+	// The view is given per invocation, hence, it is set explicitly
+	for _, view := range []string{core.ViewWeek, core.ViewTeam} {
+		t.Run(view, func(t *testing.T) {
+			config := readConfig(t, filepath.Join("testdata", "view", view+".json"))
+			config.View = view
+			plotGolden(t, filepath.Join("testdata", "view", view+".golden"), config, true, testTime)
+		})
+	}
+}